@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/eklownr/gorpg/achievements"
+)
+
+const (
+	profileFile    = saveDir + "/profile.json"
+	ticksPerSecond = 60
+	walkStep       = imgSize // longest Player step that count as walking, scene borders and doors move further
+)
+
+// Profile is the lifetime stats and the unlocked achievements. It's saved in
+// its own file next to the save and kept over new games
+type Profile struct {
+	Harvested map[string]int `json:"harvested"` // crops by type
+	Earned    int            `json:"earned"`    // coins
+	Delivered int            `json:"delivered"` // chickens
+	Walked    float64        `json:"walked"`    // pixels
+	PlayTicks int            `json:"playTicks"`
+	Unlocked  []string       `json:"unlocked"` // achievement names in unlock order
+}
+
+var profile = newProfile()
+
+func newProfile() Profile {
+	return Profile{Harvested: make(map[string]int)}
+}
+
+// AchievementUnlocked is an achievement the Player just got
+type AchievementUnlocked struct {
+	Achievement *achievements.Achievement
+}
+
+// load profile.json
+func loadProfile() {
+	content, err := os.ReadFile(profileFile)
+	if err != nil {
+		return // first start
+	}
+	p := newProfile()
+	if err := json.Unmarshal(content, &p); err != nil {
+		log.Println("profile:", err)
+		return
+	}
+	if p.Harvested == nil {
+		p.Harvested = make(map[string]int)
+	}
+	profile = p
+}
+
+// save profile.json
+func saveProfile() {
+	content, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		log.Println("profile:", err)
+		return
+	}
+	if err := os.MkdirAll(saveDir, 0755); err != nil {
+		log.Println("profile:", err)
+		return
+	}
+	if err := os.WriteFile(profileFile, content, 0644); err != nil {
+		log.Println("profile:", err)
+	}
+}
+
+// lifetime stats the achievement rules can use
+func (g *Game) profileStats() map[string]int {
+	stats := map[string]int{
+		"earned":    profile.Earned,
+		"delivered": profile.Delivered,
+		"walked":    int(profile.Walked / imgSize), // tiles
+		"minutes":   profile.PlayTicks / ticksPerSecond / 60,
+	}
+	for crop, n := range profile.Harvested {
+		stats["harvested"] += n
+		stats["harvested."+crop] = n
+	}
+	return stats
+}
+
+// count harvests, coins and chickens for the lifetime stats
+func subscribeProfile(b *EventBus) {
+	Subscribe(b, func(g *Game, e ItemPicked) {
+		if _, ok := g.crops.Get(e.Item); ok {
+			profile.Harvested[e.Item] += e.Count
+		}
+	})
+	Subscribe(b, func(g *Game, e CoinEarned) { profile.Earned += e.Count })
+	Subscribe(b, func(g *Game, e ChickenDelivered) { profile.Delivered++ })
+	Subscribe(b, func(g *Game, e AchievementUnlocked) { playSound(audioSecret) })
+}
+
+// play time and distance walked. Achievements are checked once a second
+func (g *Game) updateProfile() {
+	profile.PlayTicks++
+	if step := math.Hypot(g.Player.pos.x-g.Player.prePos.x, g.Player.pos.y-g.Player.prePos.y); step < walkStep {
+		profile.Walked += step
+	}
+	if profile.PlayTicks%ticksPerSecond == 0 {
+		g.checkAchievements()
+	}
+}
+
+// unlock achievements when their rules pass
+func (g *Game) checkAchievements() {
+	stats := g.profileStats()
+	for _, a := range g.achievements.Achievements {
+		if !slices.Contains(profile.Unlocked, a.Name) && a.Reached(stats) {
+			profile.Unlocked = append(profile.Unlocked, a.Name)
+			saveProfile()
+			Publish(g, AchievementUnlocked{a})
+		}
+	}
+}
+
+// lifetime stats in the left column and the achievements in the right column
+// of the achievements menu. Locked achievements are gray
+func (g *Game) achievementRows() {
+	m := g.menu
+	stats := g.profileStats()
+	m.addRow(tr("Statistics"), yellow, 0)
+	m.addRow(fmt.Sprintf("%s: %d", tr("Crops harvested"), stats["harvested"]), white, 0)
+	for _, crop := range g.crops.Crops {
+		if n := profile.Harvested[crop.Name]; n > 0 {
+			m.addRow(fmt.Sprintf("  %s: %d", crop.Name, n), white, 0)
+		}
+	}
+	m.addRow(fmt.Sprintf("%s: %d", tr("Coins earned"), profile.Earned), white, 0)
+	m.addRow(fmt.Sprintf("%s: %d", tr("Chickens delivered"), profile.Delivered), white, 0)
+	m.addRow(fmt.Sprintf("%s: %d %s", tr("Distance walked"), stats["walked"], tr("tiles")), white, 0)
+	m.addRow(fmt.Sprintf("%s: %s", tr("Play time"), playTime(profile.PlayTicks)), white, 0)
+
+	m.addRow(fmt.Sprintf("%s %d/%d", tr("Achievements"), len(profile.Unlocked), len(g.achievements.Achievements)), yellow, 1)
+	for _, a := range g.achievements.Achievements {
+		c := gray
+		if slices.Contains(profile.Unlocked, a.Name) {
+			c = white
+		}
+		m.addRow(a.Name+" - "+a.Description, c, 1)
+	}
+}
+
+// play time like "1h 05m"
+func playTime(ticks int) string {
+	minutes := ticks / ticksPerSecond / 60
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,67 @@
+package achievements
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/eklownr/gorpg/tiers"
+)
+
+// Achievement unlock once when all rules pass against the lifetime stats,
+// like {"stat": "harvested.wheat", "op": ">=", "value": 50}
+type Achievement struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Icon        string       `json:"icon"` // item shown in the list and the toast
+	If          []tiers.Rule `json:"if"`
+}
+
+type AchievementsJSON struct {
+	Achievements []*Achievement `json:"achievements"`
+	byName       map[string]*Achievement
+}
+
+func NewAchievementsJSON(filepath string) (*AchievementsJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var achievementsJSON AchievementsJSON
+	err = json.Unmarshal(content, &achievementsJSON)
+	if err != nil {
+		return nil, err
+	}
+	achievementsJSON.byName = make(map[string]*Achievement)
+	for _, a := range achievementsJSON.Achievements {
+		if achievementsJSON.byName[a.Name] != nil {
+			return nil, fmt.Errorf("achievements: %s is defined twice", a.Name)
+		}
+		if len(a.If) == 0 {
+			return nil, fmt.Errorf("achievements: %s has no rules", a.Name)
+		}
+		for _, r := range a.If {
+			if err := r.Validate(); err != nil {
+				return nil, fmt.Errorf("achievements: %s: %w", a.Name, err)
+			}
+		}
+		achievementsJSON.byName[a.Name] = a
+	}
+	return &achievementsJSON, nil
+}
+
+// Get achievement by name
+func (a *AchievementsJSON) Get(name string) (*Achievement, bool) {
+	achievement, ok := a.byName[name]
+	return achievement, ok
+}
+
+// Reached when all rules pass
+func (a *Achievement) Reached(stats map[string]int) bool {
+	for _, r := range a.If {
+		if !r.Check(stats) {
+			return false
+		}
+	}
+	return true
+}
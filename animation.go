@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math/rand"
+
+	"github.com/eklownr/gorpg/sheets"
+)
+
+const (
+	walkTicks = 15 // ticks for every walk and idle frame
+	coinTicks = 8  // coin2.json has no durations
+	msPerTick = 1000 / 60
+)
+
+// LoopMode is what a clip do after the last frame
+type LoopMode int
+
+const (
+	LoopForever  LoopMode = iota // start over from the first frame
+	LoopOnce                     // stay on the last frame
+	LoopPingPong                 // play backwards to the first frame and forward again
+)
+
+// Clip is frames in a sprite sheet with the ticks every frame is shown
+type Clip struct {
+	frames    []image.Rectangle
+	durations []int
+	loop      LoopMode
+}
+
+// Animation is the named clips for one kind of sprite
+type Animation map[string]*Clip
+
+// new clip where every frame is shown ticks
+func NewClip(loop LoopMode, ticks int, frames ...image.Rectangle) *Clip {
+	c := &Clip{frames: frames, loop: loop}
+	for range frames {
+		c.durations = append(c.durations, ticks)
+	}
+	return c
+}
+
+// rect of cell col,row in a sheet with size*size cells
+func cell(col, row, size int) image.Rectangle {
+	return image.Rect(col*size, row*size, (col+1)*size, (row+1)*size)
+}
+
+// n frames w*h in a row from x,y
+func strip(x, y, w, h, n int) []image.Rectangle {
+	var frames []image.Rectangle
+	for i := range n {
+		frames = append(frames, image.Rect(x+i*w, y, x+(i+1)*w, y+h))
+	}
+	return frames
+}
+
+// Player and workers in the 48*48 character sheets
+var characterAnim = Animation{
+	"idle":  NewClip(LoopForever, walkTicks, cell(0, 0, imgSize), cell(1, 0, imgSize)),
+	"down":  NewClip(LoopForever, walkTicks, cell(2, 0, imgSize), cell(3, 0, imgSize)),
+	"up":    NewClip(LoopForever, walkTicks, cell(2, 1, imgSize), cell(3, 1, imgSize)),
+	"left":  NewClip(LoopForever, walkTicks, cell(2, 2, imgSize), cell(3, 2, imgSize)),
+	"right": NewClip(LoopForever, walkTicks, cell(0, 3, imgSize), cell(2, 3, imgSize)),
+}
+
+// spinning coin and chicken walk, loaded from the sprite sheets in main
+var (
+	coinAnim    Animation
+	chickenAnim Animation
+)
+
+// burning loop in Fire.png, the first frames is the fire starting
+var fireAnim = Animation{
+	"burn": NewClip(LoopForever, fireTicks, strip(fireFirst*fireFrame, 0, fireFrame, 12, fireFrames)...),
+}
+
+// Animator play the clips of an Animation for one sprite
+type Animator struct {
+	anim  Animation
+	name  string
+	clip  *Clip
+	frame int
+	ticks int  // ticks the frame has been shown
+	back  bool // ping-pong clip is playing backwards
+	done  bool // LoopOnce clip is on the last frame
+}
+
+func NewAnimator(anim Animation, name string) *Animator {
+	return &Animator{anim: anim, name: name, clip: anim[name]}
+}
+
+// new animator that start on a random frame, so sprites of the same kind don't move in step
+func newRandomAnimator(anim Animation, name string) *Animator {
+	a := NewAnimator(anim, name)
+	a.frame = rand.Intn(len(a.clip.frames))
+	return a
+}
+
+// Play clip name. The frame is kept when changing clip, so walking turns smoothly
+func (a *Animator) Play(name string) {
+	if name == a.name {
+		return
+	}
+	a.name = name
+	a.clip = a.anim[name]
+	a.frame %= len(a.clip.frames)
+	a.done = false
+}
+
+// Restart the clip from the first frame
+func (a *Animator) Restart() {
+	a.frame, a.ticks, a.back, a.done = 0, 0, false, false
+}
+
+// Done when a LoopOnce clip is on the last frame
+func (a *Animator) Done() bool {
+	return a.done
+}
+
+// Update the animation one tick
+func (a *Animator) Update() {
+	if a.done {
+		return
+	}
+	a.ticks++
+	if a.ticks < a.clip.durations[a.frame] {
+		return
+	}
+	a.ticks = 0
+	last := len(a.clip.frames) - 1
+	switch a.clip.loop {
+	case LoopForever:
+		a.frame = (a.frame + 1) % len(a.clip.frames)
+	case LoopOnce:
+		if a.frame < last {
+			a.frame++
+		}
+		a.done = a.frame == last
+	case LoopPingPong:
+		if a.frame == last {
+			a.back = true
+		} else if a.frame == 0 {
+			a.back = false
+		}
+		if last == 0 {
+			return
+		}
+		if a.back {
+			a.frame--
+		} else {
+			a.frame++
+		}
+	}
+}
+
+// Rect of the frame in the sprite sheet
+func (a *Animator) Rect() image.Rectangle {
+	return a.clip.frames[a.frame]
+}
+
+// all tags in a sprite sheet as an Animation. Frames without a duration are shown ticks
+func sheetAnimation(s *sheets.SheetJSON, ticks int) Animation {
+	anim := Animation{}
+	for _, name := range s.AnimNames() {
+		a, _ := s.Anim(name)
+		loop := LoopForever
+		if a.Direction == "pingpong" || a.Direction == "pingpong_reverse" {
+			loop = LoopPingPong
+		} else if a.Once {
+			loop = LoopOnce
+		}
+		c := &Clip{loop: loop}
+		for _, f := range a.Frames {
+			c.frames = append(c.frames, f.Rect)
+			c.durations = append(c.durations, frameTicks(f.Duration, ticks))
+		}
+		anim[name] = c
+	}
+	return anim
+}
+
+// milliseconds to Update ticks
+func frameTicks(ms, ticks int) int {
+	if ms == 0 {
+		return ticks
+	}
+	return max(1, (ms+msPerTick/2)/msPerTick)
+}
+
+// rect of a named slice or frame in the sprite sheet
+func sheetRect(s *sheets.SheetJSON, name string) image.Rectangle {
+	r, ok := s.Rect(name)
+	if !ok {
+		checkErr(fmt.Errorf("sheets: %s has no %s", s.Image, name))
+	}
+	return r
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"github.com/eklownr/gorpg/achievements"
+	"github.com/eklownr/gorpg/buildings"
+	"github.com/eklownr/gorpg/crops"
+	"github.com/eklownr/gorpg/dialogues"
+	"github.com/eklownr/gorpg/interiors"
+	"github.com/eklownr/gorpg/quests"
+	"github.com/eklownr/gorpg/sheets"
+	"github.com/eklownr/gorpg/tiers"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Assets are the images and data files that don't change while playing.
+// They are loaded once, New Game build the new Game from the same assets
+type Assets struct {
+	crops          *crops.CropsJSON
+	tiers          *tiers.TiersJSON
+	buildings      *buildings.BuildingsJSON
+	interiors      *interiors.InteriorsJSON
+	dialogues      *dialogues.DialoguesJSON
+	quests         *quests.QuestsJSON
+	achievements   *achievements.AchievementsJSON
+	atlas          *Atlas
+	buildingImgs   map[string]*ebiten.Image
+	oldVillage     *sheets.SheetJSON
+	newVillage     *sheets.SheetJSON
+	coin           *sheets.SheetJSON
+	chicken        *sheets.SheetJSON
+	tilemapImg     *ebiten.Image
+	bgSeasons      *Seasonal
+	tilesetSeasons *Seasonal
+	waterSeasons   *Seasonal
+	lightImg       *ebiten.Image
+}
+
+var assets *Assets
+
+// assets are loaded at the first call
+func gameAssets() *Assets {
+	if assets == nil {
+		assets = loadAssets()
+	}
+	return assets
+}
+
+func loadAssets() *Assets {
+	a := &Assets{}
+	var err error
+
+	// crop registry
+	a.crops, err = crops.NewCropsJSON("assets/data/crops.json")
+	checkErr(err)
+
+	// village progression tiers
+	a.tiers, err = tiers.NewTiersJSON("assets/data/tiers.json")
+	checkErr(err)
+
+	// building blueprints
+	a.buildings, err = buildings.NewBuildingsJSON("assets/data/buildings.json")
+	checkErr(err)
+
+	// house interiors
+	a.interiors, err = interiors.NewInteriorsJSON("assets/data/interiors.json")
+	checkErr(err)
+
+	// dialogues for the budda, workers and villagers
+	a.dialogues, err = dialogues.NewDialoguesJSON("assets/data/dialogues.json")
+	checkErr(err)
+
+	// quests with objectives and rewards
+	a.quests, err = quests.NewQuestsJSON("assets/data/quests.json")
+	checkErr(err)
+	checkErr(checkDialogueQuests(a.dialogues, a.quests))
+
+	// achievements for the lifetime stats
+	a.achievements, err = achievements.NewAchievementsJSON("assets/data/achievements.json")
+	checkErr(err)
+
+	// pack the sprite images and the building images in the atlas
+	a.atlas = NewAtlas()
+	for _, path := range atlasImages {
+		checkErr(a.atlas.Load(path))
+	}
+	for _, bp := range a.buildings.Blueprints {
+		checkErr(a.atlas.Load(bp.Image))
+	}
+	a.atlas.Build()
+	drawStats.atlas = a.atlas
+	a.buildingImgs = make(map[string]*ebiten.Image)
+	for _, bp := range a.buildings.Blueprints {
+		a.buildingImgs[bp.Image] = a.atlas.Image(bp.Image)
+	}
+
+	// village sheets
+	a.oldVillage, err = sheets.NewSheetJSON("assets/images/village_old.json")
+	checkErr(err)
+	a.newVillage, err = sheets.NewSheetJSON("assets/images/TilesetHouse.json")
+	checkErr(err)
+
+	// coin and chicken animations
+	a.coin, err = sheets.NewSheetJSON("assets/images/coin2.json")
+	checkErr(err)
+	coinAnim = sheetAnimation(a.coin, coinTicks)
+	a.chicken, err = sheets.NewSheetJSON("assets/images/chicken.json")
+	checkErr(err)
+	chickenAnim = sheetAnimation(a.chicken, coinTicks)
+
+	// tilesets and background, with a palette for every season
+	tilemapImg, tilemapSrc, err := ebitenutil.NewImageFromFile("assets/map/tileset_floor.png")
+	checkErr(err)
+	_, tilemapWaterSrc, err := ebitenutil.NewImageFromFile("assets/map/TilesetWater.png")
+	checkErr(err)
+	_, bgSrc, err := ebitenutil.NewImageFromFile("assets/images/grass.png")
+	checkErr(err)
+	a.tilemapImg = tilemapImg
+	a.bgSeasons = newSeasonal(bgSrc)
+	a.tilesetSeasons = newSeasonal(tilemapSrc)
+	a.waterSeasons = newSeasonal(tilemapWaterSrc)
+
+	a.lightImg = newLightImg()
+	return a
+}
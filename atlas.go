@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	atlasSize    = 2048 // atlas page width and height
+	atlasPadding = 1    // edge pixels are extruded into the padding, no bleeding when scaled
+)
+
+// sprite images packed in the atlas at startup
+var atlasImages = []string{
+	"assets/images/playerBlue.png",
+	"assets/images/player.png",
+	"assets/images/workers.png",
+	"assets/images/coin2.png",
+	"assets/images/chicken.png",
+	"assets/images/Egg.png",
+	"assets/images/Chicken_House.png",
+	"assets/images/InfoBox.png",
+	"assets/images/plants.png",
+	"assets/images/Chest.png",
+	"assets/images/Fire.png",
+	"assets/images/biom.png",
+	"assets/images/smoke.png",
+	"assets/images/village_old.png",
+	"assets/images/TilesetHouse.png",
+}
+
+// Atlas pack many small images into a few large pages. Sprites from
+// different files are drawn from the same texture, so the draws can be batched
+type Atlas struct {
+	entries []*atlasEntry
+	byPath  map[string]*atlasEntry
+	pages   []*ebiten.Image
+	pageOf  map[*ebiten.Image]*ebiten.Image // handle to page
+}
+
+type atlasEntry struct {
+	path   string
+	src    image.Image
+	page   int
+	rect   image.Rectangle // place in the page, without padding
+	handle *ebiten.Image
+}
+
+func NewAtlas() *Atlas {
+	return &Atlas{byPath: make(map[string]*atlasEntry), pageOf: make(map[*ebiten.Image]*ebiten.Image)}
+}
+
+// Load image file to be packed by Build
+func (a *Atlas) Load(path string) error {
+	if a.byPath[path] != nil {
+		return nil
+	}
+	f, err := ebitenutil.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("atlas: %s: %w", path, err)
+	}
+	b := src.Bounds()
+	if b.Dx()+2*atlasPadding > atlasSize || b.Dy()+2*atlasPadding > atlasSize {
+		return fmt.Errorf("atlas: %s is bigger than the atlas page", path)
+	}
+	e := &atlasEntry{path: path, src: src}
+	a.entries = append(a.entries, e)
+	a.byPath[path] = e
+	return nil
+}
+
+// Build pack the loaded images in shelves, the tallest images first
+func (a *Atlas) Build() {
+	sorted := slices.Clone(a.entries)
+	slices.SortStableFunc(sorted, func(x, y *atlasEntry) int {
+		return y.src.Bounds().Dy() - x.src.Bounds().Dy()
+	})
+	var pages []*image.NRGBA
+	x, y, shelf := 0, 0, 0
+	for _, e := range sorted {
+		w := e.src.Bounds().Dx() + 2*atlasPadding
+		h := e.src.Bounds().Dy() + 2*atlasPadding
+		if x+w > atlasSize { // next shelf
+			x, y, shelf = 0, y+shelf, 0
+		}
+		if len(pages) == 0 || y+h > atlasSize { // next page
+			pages = append(pages, image.NewNRGBA(image.Rect(0, 0, atlasSize, atlasSize)))
+			x, y, shelf = 0, 0, 0
+		}
+		e.page = len(pages) - 1
+		e.rect = image.Rect(x+atlasPadding, y+atlasPadding, x+w-atlasPadding, y+h-atlasPadding)
+		blit(pages[e.page], e.rect, e.src)
+		x += w
+		shelf = max(shelf, h)
+	}
+	for _, p := range pages {
+		a.pages = append(a.pages, ebiten.NewImageFromImage(p))
+	}
+	for _, e := range a.entries {
+		page := a.pages[e.page]
+		e.handle = page.SubImage(e.rect).(*ebiten.Image)
+		a.pageOf[e.handle] = page
+		e.src = nil
+	}
+}
+
+// draw src at r and extrude the edge pixels into the padding
+func blit(dst *image.NRGBA, r image.Rectangle, src image.Image) {
+	draw.Draw(dst, r, src, src.Bounds().Min, draw.Src)
+	for p := 1; p <= atlasPadding; p++ {
+		for x := r.Min.X - p; x < r.Max.X+p; x++ {
+			cx := min(max(x, r.Min.X), r.Max.X-1)
+			dst.Set(x, r.Min.Y-p, dst.At(cx, r.Min.Y))
+			dst.Set(x, r.Max.Y-1+p, dst.At(cx, r.Max.Y-1))
+		}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			dst.Set(r.Min.X-p, y, dst.At(r.Min.X, y))
+			dst.Set(r.Max.X-1+p, y, dst.At(r.Max.X-1, y))
+		}
+	}
+}
+
+// Image packed from path. The image don't start at 0,0, draw parts of it with subImage
+func (a *Atlas) Image(path string) *ebiten.Image {
+	e := a.byPath[path]
+	if e == nil || e.handle == nil {
+		checkErr(fmt.Errorf("atlas: %s is not packed", path))
+	}
+	return e.handle
+}
+
+// page texture of img, img itself if it's not in the atlas
+func (a *Atlas) page(img *ebiten.Image) *ebiten.Image {
+	if p, ok := a.pageOf[img]; ok {
+		return p
+	}
+	return img
+}
+
+// DrawStats count sprite draws and texture switches in one frame. Without
+// the atlas every image is its own texture, so both counts are measured in
+// the same frame. Shapes and text also break batches and are not counted
+type DrawStats struct {
+	atlas    *Atlas
+	now      drawCounts // the frame being drawn
+	last     drawCounts // the last whole frame
+	lastPage *ebiten.Image
+	lastImg  *ebiten.Image
+}
+
+type drawCounts struct {
+	sprites  int
+	batches  int // texture switches with the atlas
+	unpacked int // texture switches if every image was its own texture
+}
+
+var drawStats DrawStats
+
+func (s *DrawStats) count(img *ebiten.Image) {
+	s.now.sprites++
+	if page := s.atlas.page(img); page != s.lastPage {
+		s.now.batches++
+		s.lastPage = page
+	}
+	if img != s.lastImg {
+		s.now.unpacked++
+		s.lastImg = img
+	}
+}
+
+// start counting a new frame
+func (s *DrawStats) frame() {
+	s.last, s.now = s.now, drawCounts{}
+	s.lastPage, s.lastImg = nil, nil
+}
+
+func (s *DrawStats) String() string {
+	return fmt.Sprintf("sprites %d  batches %d, %d without atlas  pages %d", s.last.sprites, s.last.batches, s.last.unpacked, len(s.atlas.pages))
+}
+
+// part r of img in img coordinates. Atlas images don't start at 0,0, so r
+// is moved to where img is in the page and clipped to img
+func subImage(img *ebiten.Image, r image.Rectangle) *ebiten.Image {
+	drawStats.count(img)
+	return img.SubImage(r.Add(img.Bounds().Min).Intersect(img.Bounds())).(*ebiten.Image)
+}
+
+// draw sprite draws and texture batches of the last frame
+func (g *Game) drawDebug(screen *ebiten.Image) {
+	if !g.debugOpen {
+		return
+	}
+	vector.DrawFilledRect(screen, 0, screenHeight-20, screenWidth, 20, blue_transp, true)
+	addTextAt(screen, 10, fmt.Sprintf("%s  fps %.0f", drawStats.String(), ebiten.ActualFPS()), white, 8, screenHeight-16)
+}
@@ -0,0 +1,82 @@
+package buildings
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+)
+
+// Effect is what a finished building give the village
+type Effect struct {
+	Storage int  `json:"storage"` // slots in the building storage
+	Housing int  `json:"housing"` // workers that can live in the building
+	Shop    bool `json:"shop"`    // trade with the budda market in the village
+	Coop    int  `json:"coop"`    // chickens that can live in the coop
+}
+
+// Blueprint is one building the Player can build. Rect is the finished
+// building in Image. Cost is the items delivered to the construction site
+// and Work is the ticks workers and Player build before it is done.
+type Blueprint struct {
+	Name   string         `json:"name"`
+	Image  string         `json:"image"`
+	Rect   [4]int         `json:"rect"`
+	Cost   map[string]int `json:"cost"`
+	Work   int            `json:"work"`
+	Stages int            `json:"stages"`
+	Effect Effect         `json:"effect"`
+}
+
+type BuildingsJSON struct {
+	Blueprints []*Blueprint `json:"blueprints"`
+	byName     map[string]*Blueprint
+}
+
+func NewBuildingsJSON(filepath string) (*BuildingsJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var buildingsJSON BuildingsJSON
+	err = json.Unmarshal(content, &buildingsJSON)
+	if err != nil {
+		return nil, err
+	}
+	buildingsJSON.byName = make(map[string]*Blueprint)
+	for _, b := range buildingsJSON.Blueprints {
+		if b.Bounds().Empty() || b.Work <= 0 || b.Stages <= 0 {
+			return nil, fmt.Errorf("buildings: %s needs rect, work and stages", b.Name)
+		}
+		buildingsJSON.byName[b.Name] = b
+	}
+	return &buildingsJSON, nil
+}
+
+// Get blueprint by name
+func (b *BuildingsJSON) Get(name string) (*Blueprint, bool) {
+	blueprint, ok := b.byName[name]
+	return blueprint, ok
+}
+
+// Bounds of the finished building in the image
+func (b *Blueprint) Bounds() image.Rectangle {
+	return image.Rect(b.Rect[0], b.Rect[1], b.Rect[2], b.Rect[3])
+}
+
+// Materials is the number of items in the cost
+func (b *Blueprint) Materials() int {
+	n := 0
+	for _, count := range b.Cost {
+		n += count
+	}
+	return n
+}
+
+// Stage for a construction site with work done, Stages when the building is done
+func (b *Blueprint) Stage(work int) int {
+	if work >= b.Work {
+		return b.Stages
+	}
+	return work * b.Stages / b.Work
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	chestItem       = "chest"
+	chestSlots      = 18
+	chestFrames     = 5  // open animation in Chest.png, first frame is closed
+	chestFrameTicks = 6  // ticks for every frame in the open animation
+	chestCellSize   = 48 // every frame in Chest.png is a 48*48 cell
+	chestReach      = imgSize
+)
+
+// StorageChest is a chest the Player has placed, with its own inventory
+type StorageChest struct {
+	*Sprite
+	inv   *Inventory
+	open  bool
+	scene int // chests are only seen and used in their scene
+}
+
+// new closed chest at pos
+func (g *Game) newChest(pos Point) *StorageChest {
+	return &StorageChest{
+		Sprite: &Sprite{
+			img:    g.chestImg,
+			pos:    pos,
+			active: true,
+		},
+		inv: NewInventory(chestSlots, stackSize),
+	}
+}
+
+// chest frame rectangle in Chest.png. The open lid is higher than the chest
+func chestFrameRect(frame int) image.Rectangle {
+	x := chestCellSize*frame + 16
+	return image.Rect(x, 8, x+16, 32)
+}
+
+// E key talk to the villager next to the Player, open or close the chest next to
+// the Player, or place a chest from the hotbar. In the build menu E place the construction site
+func (g *Game) interactKey() {
+	if g.buildMode {
+		g.placeSite()
+		return
+	}
+	if name := g.nearSpeaker(); name != "" && g.talkTo(name) {
+		g.closeChest()
+		return
+	}
+	if c := g.nearChest(); c != nil {
+		if g.openChest == c {
+			g.closeChest()
+		} else {
+			g.closeChest()
+			c.open = true
+			g.openChest = c
+			playSound(audioChest)
+		}
+		return
+	}
+	if g.activeItem().Item == chestItem && g.inside == nil {
+		g.placeChest()
+	}
+}
+
+// place chest on the tile under the Player
+func (g *Game) placeChest() {
+	x, y := g.playerTile()
+	pos := Point{float64(x * tileSize), float64(y * tileSize)}
+	for _, c := range g.chests {
+		if c.pos == pos && c.scene == g.scene {
+			return
+		}
+	}
+	if g.Player.inv.Remove(chestItem, 1) == 0 {
+		return
+	}
+	c := g.newChest(pos)
+	c.scene = g.scene
+	g.chests = append(g.chests, c)
+	g.puff(Point{pos.x + 8, pos.y + 8})
+	playSound(audioFx)
+}
+
+// close the open chest
+func (g *Game) closeChest() {
+	if g.openChest == nil {
+		return
+	}
+	g.openChest.open = false
+	g.openChest = nil
+	g.drag = nil
+	playSound(audioChest)
+}
+
+// closest chest or building storage the Player can reach
+func (g *Game) nearChest() *StorageChest {
+	var near *StorageChest
+	best := float64(chestReach)
+	feet := Point{g.Player.pos.x + imgSize/2, g.Player.pos.y + imgSize*3/4}
+	for _, c := range g.storages() {
+		d := math.Hypot(c.pos.x+8-feet.x, c.pos.y+8-feet.y)
+		if d < best {
+			near, best = c, d
+		}
+	}
+	return near
+}
+
+// run open and close animation. Close the chest if the Player walk away
+func (g *Game) updateChests() {
+	if g.openChest != nil && g.nearChest() != g.openChest {
+		g.closeChest()
+	}
+	for _, c := range g.storages() {
+		c.frameCounter++
+		if c.frameCounter < chestFrameTicks {
+			continue
+		}
+		c.frameCounter = 0
+		if c.open && c.frame < chestFrames-1 {
+			c.frame++
+		} else if !c.open && c.frame > 0 {
+			c.frame--
+		}
+	}
+}
+
+// shift click move the whole stack to the other inventory
+func (g *Game) quickTransfer() {
+	if g.openChest == nil || !ebiten.IsKeyPressed(ebiten.KeyShift) || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	x, y := ebiten.CursorPosition()
+	for _, v := range g.invViews() {
+		i := v.slotAt(x, y)
+		if i < 0 || v.inv.Slot(i).Count == 0 {
+			continue
+		}
+		to := g.openChest.inv
+		if v.inv == g.openChest.inv {
+			to = g.Player.inv
+		}
+		s := v.inv.Slot(i)
+		if to == g.Player.inv && s.Item == coinItem { // only what fit in the wallet
+			s.Count = min(s.Count, g.walletRoom())
+			if s.Count == 0 {
+				Publish(g, ActionFailed{"Wallet full", coinItem})
+				return
+			}
+		}
+		v.inv.RemoveAt(i, to.Add(s.Item, s.Count))
+		g.drag = nil
+		return
+	}
+}
+
+// chest inventory above the bag
+func (g *Game) chestView() invView {
+	bag := g.bagView()
+	slots := len(g.openChest.inv.slots)
+	rows := (slots + inventoryCols - 1) / inventoryCols
+	return invView{g.openChest.inv, 0, slots, bag.x, bag.y - float64(slotSize*rows) - 12}
+}
+
+// draw a placed chest
+func (g *Game) drawChest(screen *ebiten.Image, c *StorageChest) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(c.pos.x, c.pos.y-8) // the lid is above the tile
+	screen.DrawImage(subImage(c.img, chestFrameRect(c.frame)), op)
+}
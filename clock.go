@@ -0,0 +1,115 @@
+package main
+
+import "fmt"
+
+const (
+	minuteTicks = 5 // Update ticks for one game minute, one day is 2 min
+	dayMinutes  = 24 * 60
+	dayTicks    = dayMinutes * minuteTicks
+	startHour   = 6  // the game start in the morning
+	duskHour    = 19 // workers go home
+	dawnHour    = 6  // workers go back to work
+	seasonDays  = 7  // days in one season
+	everyHour   = -1 // event hour for events that run every hour
+	yearDays    = seasonDays * len(seasonNames)
+)
+
+// seasons in the calendar, crops use the names in crops.json
+var seasonNames = [...]string{"spring", "summer", "autumn", "winter"}
+
+// Clock is the in-game time in Update ticks since day 0 at midnight
+type Clock struct {
+	ticks int
+}
+
+// Tick the clock one Update tick. Return true when a new minute start
+func (c *Clock) Tick() bool {
+	c.ticks++
+	return c.ticks%minuteTicks == 0
+}
+
+// Day since the game started
+func (c Clock) Day() int {
+	return c.ticks / dayTicks
+}
+
+// TimeOfDay in minutes since midnight
+func (c Clock) TimeOfDay() int {
+	return c.ticks % dayTicks / minuteTicks
+}
+
+// Hour of the day 0-23
+func (c Clock) Hour() int {
+	return c.TimeOfDay() / 60
+}
+
+// Minute of the hour 0-59
+func (c Clock) Minute() int {
+	return c.TimeOfDay() % 60
+}
+
+// Year since the game started, the first year is 1
+func (c Clock) Year() int {
+	return c.Day()/yearDays + 1
+}
+
+// Season index 0-3 in seasonNames
+func (c Clock) Season() int {
+	return c.Day() % yearDays / seasonDays
+}
+
+// SeasonName like "spring"
+func (c Clock) SeasonName() string {
+	return seasonNames[c.Season()]
+}
+
+// DayOfSeason 1-seasonDays
+func (c Clock) DayOfSeason() int {
+	return c.Day()%seasonDays + 1
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%s %d  %02d:%02d", c.SeasonName(), c.DayOfSeason(), c.Hour(), c.Minute())
+}
+
+// timeEvent run every day at hour:minute, or every hour at minute
+type timeEvent struct {
+	hour, minute int
+	run          func(g *Game)
+}
+
+// events scheduled by time of day
+var schedule = []timeEvent{
+	{0, 0, (*Game).newDay},
+	{dawnHour, 0, (*Game).dawn},
+	{duskHour, 0, (*Game).dusk},
+	{everyHour, 0, (*Game).weatherHour},
+}
+
+// tick the clock and run the events scheduled for this minute
+func (g *Game) updateClock() {
+	if !g.clock.Tick() {
+		return
+	}
+	for _, e := range schedule {
+		if (e.hour == everyHour || e.hour == g.clock.Hour()) && e.minute == g.clock.Minute() {
+			e.run(g)
+		}
+	}
+}
+
+// workers go back to work in the morning
+func (g *Game) dawn() {
+	g.workersHome = false
+}
+
+// workers go home at dusk
+func (g *Game) dusk() {
+	g.workersHome = true
+}
+
+// workers are at home in the night
+func (g *Game) nightTime() bool {
+	h := g.clock.Hour()
+	return h >= duskHour || h < dawnHour
+}
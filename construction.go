@@ -0,0 +1,405 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/eklownr/gorpg/buildings"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	woodItem     = "wood"
+	stoneItem    = "stone"
+	baseHousing  = 4  // workers living in the old village houses
+	deliverTicks = 15 // ticks between every item the Player deliver to a site
+	buildPanelW  = 220
+	buildPanelY  = 24
+	buildRow     = 28
+)
+
+// Building is a construction site until all work is done, then a building
+type Building struct {
+	*Sprite
+	blueprint *buildings.Blueprint
+	delivered map[string]int // items delivered to the site
+	work      int            // ticks of work done
+	store     *StorageChest  // storage in a finished building
+	roof      float32        // roof fade, 0 is solid
+}
+
+// new construction site for blueprint at pos
+func (g *Game) newBuilding(bp *buildings.Blueprint, pos Point) *Building {
+	return &Building{
+		Sprite: &Sprite{
+			img:     g.buildingImgs[bp.Image],
+			pos:     pos,
+			rectPos: bp.Bounds(),
+			active:  true,
+		},
+		blueprint: bp,
+		delivered: make(map[string]int),
+	}
+}
+
+// all work is done
+func (b *Building) done() bool {
+	return b.work >= b.blueprint.Work
+}
+
+// items of item the site still need
+func (b *Building) needs(item string) int {
+	return b.blueprint.Cost[item] - b.delivered[item]
+}
+
+// work can only go as far as the delivered materials
+func (b *Building) maxWork() int {
+	total := b.blueprint.Materials()
+	if total == 0 {
+		return b.blueprint.Work
+	}
+	delivered := 0
+	for _, n := range b.delivered {
+		delivered += n
+	}
+	return b.blueprint.Work * delivered / total
+}
+
+// building rectangle on screen
+func (b *Building) footprint() image.Rectangle {
+	return image.Rect(int(b.pos.x), int(b.pos.y), int(b.pos.x)+b.rectPos.Dx(), int(b.pos.y)+b.rectPos.Dy())
+}
+
+// the lower part of the building block the Player, the roof does not
+func (b *Building) wall() image.Rectangle {
+	r := b.footprint()
+	r.Min.Y = r.Max.Y - r.Dy()/2
+	return r
+}
+
+// set up the effect of a finished building
+func (g *Game) finishBuilding(b *Building) {
+	if n := b.blueprint.Effect.Storage; n > 0 && b.store == nil {
+		door := Point{b.pos.x + float64(b.rectPos.Dx())/2 - 8, b.pos.y + float64(b.rectPos.Dy()) - 8}
+		b.store = &StorageChest{Sprite: &Sprite{pos: door}, inv: NewInventory(n, stackSize)}
+	}
+}
+
+// B key show or hide the build menu
+func (g *Game) buildKey() {
+	g.buildMode = !g.buildMode
+}
+
+// N key select the next blueprint in the build menu
+func (g *Game) nextBlueprintKey() {
+	if g.buildMode {
+		g.blueprintSel = (g.blueprintSel + 1) % len(g.buildings.Blueprints)
+	}
+}
+
+// selected blueprint in the build menu
+func (g *Game) blueprint() *buildings.Blueprint {
+	return g.buildings.Blueprints[g.blueprintSel]
+}
+
+// construction site pos for the selected blueprint, with the Player in front of the door
+func (g *Game) sitePos() Point {
+	x, y := g.playerTile()
+	r := g.blueprint().Bounds()
+	return Point{float64((x - r.Dx()/tileSize/2) * tileSize), float64(y*tileSize - r.Dy())}
+}
+
+// buildings are only built on free grass in the farm scene
+func (g *Game) canBuild(r image.Rectangle) bool {
+	if g.scene != 0 || !r.In(image.Rect(20, 20, 620, 390)) {
+		return false
+	}
+	for _, house := range g.house {
+		hr := image.Rect(int(house.pos.x), int(house.pos.y),
+			int(house.pos.x)+house.rectPos.Dx(), int(house.pos.y)+house.rectPos.Dy())
+		if house.active && hr.Overlaps(r) {
+			return false
+		}
+	}
+	for _, b := range g.buildingSites {
+		if b.footprint().Overlaps(r) {
+			return false
+		}
+	}
+	for _, c := range g.chests {
+		if c.scene == 0 && image.Rect(int(c.pos.x), int(c.pos.y), int(c.pos.x)+tileSize, int(c.pos.y)+tileSize).Overlaps(r) {
+			return false
+		}
+	}
+	for _, plant := range g.plants {
+		if image.Rect(int(plant.pos.x), int(plant.pos.y), int(plant.pos.x)+tileSize, int(plant.pos.y)+tileSize).Overlaps(r) {
+			return false
+		}
+	}
+	layer := g.farmJSON.Layer(farmLayer)
+	for y := r.Min.Y / tileSize; y*tileSize < r.Max.Y; y++ {
+		for x := r.Min.X / tileSize; x*tileSize < r.Max.X; x++ {
+			if layer.Tile(x, y) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// place a construction site for the selected blueprint
+func (g *Game) placeSite() {
+	bp := g.blueprint()
+	pos := g.sitePos()
+	if !g.canBuild(image.Rect(int(pos.x), int(pos.y), int(pos.x)+bp.Bounds().Dx(), int(pos.y)+bp.Bounds().Dy())) {
+		return
+	}
+	g.buildingSites = append(g.buildingSites, g.newBuilding(bp, pos))
+	g.buildMode = false
+	g.puff(Point{pos.x + float64(bp.Bounds().Dx())/2, pos.y + float64(bp.Bounds().Dy())/2})
+	playSound(audioFx)
+}
+
+// select blueprint with the mouse in the build menu
+func (g *Game) updateBuildMenu() {
+	if !g.buildMode || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	x, y := ebiten.CursorPosition()
+	for i := range g.buildings.Blueprints {
+		if image.Pt(x, y).In(buildMenuRow(i)) {
+			g.blueprintSel = i
+		}
+	}
+}
+
+// Player deliver materials and build. Finished buildings block the Player
+func (g *Game) updateBuildings() {
+	if g.scene != 0 {
+		return
+	}
+	player := image.Rect(
+		int(g.Player.pos.x+imgSize/4),
+		int(g.Player.pos.y+imgSize/4),
+		int(g.Player.pos.x+imgSize/2),
+		int(g.Player.pos.y+imgSize/2))
+	for _, b := range g.buildingSites {
+		if !b.wall().Overlaps(player) {
+			continue
+		}
+		g.Player.pos = g.Player.prePos
+		if b.done() {
+			if b.blueprint.Effect.Coop > 0 {
+				g.chickenHouseVisit()
+			}
+			if b.blueprint.Effect.Shop {
+				g.Player.pos.y = float64(b.wall().Max.Y - imgSize/4 + 4) // step out of the door
+				g.openMarket()
+			}
+			continue
+		}
+		b.frameCounter++
+		if b.frameCounter < deliverTicks {
+			continue
+		}
+		b.frameCounter = 0
+		if !g.deliver(b) && b.work < b.maxWork() {
+			b.work = min(b.work+deliverTicks, b.maxWork()) // Player build when there is nothing to deliver
+			r := b.footprint()
+			g.puff(Point{float64(r.Min.X) + rand.Float64()*float64(r.Dx()), float64(r.Max.Y) - 8})
+			if b.done() {
+				g.finishBuilding(b)
+				Publish(g, BuildingFinished{b})
+			}
+		}
+	}
+}
+
+// deliver one item the site need from the Player inventory
+func (g *Game) deliver(b *Building) bool {
+	for _, item := range slices.Sorted(maps.Keys(b.blueprint.Cost)) {
+		if b.needs(item) > 0 && g.Player.inv.Remove(item, 1) == 1 {
+			b.delivered[item]++
+			Publish(g, ItemDelivered{item, b})
+			return true
+		}
+	}
+	return false
+}
+
+// first construction site that has materials for more work
+func (g *Game) constructionSite() *Building {
+	for _, b := range g.buildingSites {
+		if !b.done() && b.work < b.maxWork() {
+			return b
+		}
+	}
+	return nil
+}
+
+// worker with a ripe field help on the construction site while waiting for the harvest
+func (g *Game) helpBuild(w *Characters, i int) {
+	site := g.constructionSite()
+	if site == nil || g.scene != 0 {
+		return
+	}
+	r := site.footprint()
+	w.dest = Point{float64(r.Min.X + i%4*12 - imgSize/4), float64(r.Max.Y - imgSize/2)}
+	if w.pos == w.dest && w.productive() {
+		site.work++
+		if site.done() {
+			g.finishBuilding(site)
+			Publish(g, BuildingFinished{site})
+		}
+	}
+}
+
+// workers that can live in the village
+func (g *Game) housing() int {
+	n := baseHousing
+	for _, b := range g.buildingSites {
+		if b.done() {
+			n += b.blueprint.Effect.Housing
+		}
+	}
+	return n
+}
+
+// placed chests and storage in finished buildings in this scene. Inside a house only the house storage
+func (g *Game) storages() []*StorageChest {
+	if g.inside != nil {
+		if g.inside.store == nil {
+			return nil
+		}
+		return []*StorageChest{g.inside.store}
+	}
+	var s []*StorageChest
+	for _, c := range g.chests {
+		if c.scene == g.scene {
+			s = append(s, c)
+		}
+	}
+	for _, b := range g.buildingSites {
+		if b.store != nil && b.store.scene == g.scene { // buildings are in the farm scene
+			s = append(s, b.store)
+		}
+	}
+	return s
+}
+
+// draw construction sites and finished buildings in the farm scene
+func (g *Game) drawBuildings(q *RenderQueue) {
+	if g.scene != 0 {
+		return
+	}
+	for _, b := range g.buildingSites {
+		if b.done() {
+			queueWithRoof(q, b.img, b.rectPos, b.pos, float64(b.footprint().Max.Y), b.roof)
+			continue
+		}
+		q.Add(LayerWorld, float64(b.footprint().Max.Y), func(screen *ebiten.Image) { g.drawBuilding(screen, b) })
+	}
+	if g.buildMode {
+		q.Add(LayerOverhead, 0, g.drawGhost)
+	}
+}
+
+// draw the construction stage of a site. Stage 0 is the foundation, then
+// the building grow up from the ground inside the scaffold
+func (g *Game) drawBuilding(screen *ebiten.Image, b *Building) {
+	r := b.footprint()
+	x, y, w, h := float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy())
+	vector.DrawFilledRect(screen, x, y+h*2/3, w, h/3, brown_transp, true)
+	stage := b.blueprint.Stage(b.work)
+	if stage > 0 {
+		top := b.rectPos.Max.Y - b.rectPos.Dy()*stage/b.blueprint.Stages
+		src := image.Rect(b.rectPos.Min.X, top, b.rectPos.Max.X, b.rectPos.Max.Y)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(b.pos.x, b.pos.y+float64(top-b.rectPos.Min.Y))
+		op.ColorScale.Scale(0.8, 0.8, 0.8, 1)
+		screen.DrawImage(subImage(b.img, src), op)
+	}
+	// scaffold
+	for _, px := range []float32{x + 2, x + w/2, x + w - 3} {
+		vector.StrokeLine(screen, px, y+4, px, y+h, 2, brown, true)
+	}
+	for py := y + 6; py < y+h; py += 12 {
+		vector.StrokeLine(screen, x, py, x+w, py, 1, brown, true)
+	}
+	// materials and work bars
+	bw := w * float32(b.maxWork()) / float32(b.blueprint.Work)
+	vector.DrawFilledRect(screen, x, y-8, w, 3, blue_transp, true)
+	vector.DrawFilledRect(screen, x, y-8, bw, 3, orange, true)
+	vector.DrawFilledRect(screen, x, y-4, w, 3, blue_transp, true)
+	vector.DrawFilledRect(screen, x, y-4, w*float32(b.work)/float32(b.blueprint.Work), 3, green, true)
+}
+
+// draw the selected blueprint where the site will be, red if it can't be built there
+func (g *Game) drawGhost(screen *ebiten.Image) {
+	bp := g.blueprint()
+	pos := g.sitePos()
+	r := bp.Bounds()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(pos.x, pos.y)
+	if g.canBuild(image.Rect(int(pos.x), int(pos.y), int(pos.x)+r.Dx(), int(pos.y)+r.Dy())) {
+		op.ColorScale.Scale(0.6, 1, 0.6, 0.6)
+	} else {
+		op.ColorScale.Scale(1, 0.4, 0.4, 0.6)
+	}
+	screen.DrawImage(subImage(g.buildingImgs[bp.Image], r), op)
+}
+
+// row in the build menu, used for mouse clicks
+func buildMenuRow(i int) image.Rectangle {
+	y := buildPanelY + 24 + i*buildRow
+	return image.Rect(screenWidth-buildPanelW-20, y, screenWidth-20, y+buildRow)
+}
+
+// draw build menu with blueprints, cost and effect
+func (g *Game) drawBuildMenu(screen *ebiten.Image) {
+	if !g.buildMode {
+		return
+	}
+	x := float32(screenWidth - buildPanelW - 20)
+	h := float32(24 + len(g.buildings.Blueprints)*buildRow + 20)
+	vector.DrawFilledRect(screen, x, buildPanelY, buildPanelW, h, blue_transp, true)
+	vector.StrokeRect(screen, x, buildPanelY, buildPanelW, h, 1, yellow, true)
+	addTextAt(screen, 10, fmt.Sprintf("Build   housing %d/%d", g.hiredWorkers(), g.housing()), yellow, float64(x)+8, buildPanelY+6)
+	for i, bp := range g.buildings.Blueprints {
+		r := buildMenuRow(i)
+		if i == g.blueprintSel {
+			vector.DrawFilledRect(screen, float32(r.Min.X)+2, float32(r.Min.Y), buildPanelW-4, buildRow, blue_rect, true)
+		}
+		var cost []string
+		for _, item := range slices.Sorted(maps.Keys(bp.Cost)) {
+			cost = append(cost, fmt.Sprintf("%d %s", bp.Cost[item], item))
+		}
+		addTextAt(screen, 10, bp.Name+"  "+effectText(bp.Effect), white, float64(r.Min.X)+8, float64(r.Min.Y)+2)
+		addTextAt(screen, 8, strings.Join(cost, "  "), orange, float64(r.Min.X)+8, float64(r.Min.Y)+15)
+	}
+	addTextAt(screen, 10, "n next  e place  b close", yellow, float64(x)+8, float64(buildPanelY)+float64(h)-16)
+}
+
+// short text for the building effect
+func effectText(e buildings.Effect) string {
+	var s []string
+	if e.Storage > 0 {
+		s = append(s, fmt.Sprintf("storage %d", e.Storage))
+	}
+	if e.Housing > 0 {
+		s = append(s, fmt.Sprintf("housing %d", e.Housing))
+	}
+	if e.Shop {
+		s = append(s, "shop")
+	}
+	if e.Coop > 0 {
+		s = append(s, fmt.Sprintf("coop %d", e.Coop))
+	}
+	return strings.Join(s, ", ")
+}
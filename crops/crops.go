@@ -0,0 +1,81 @@
+package crops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Crop is one crop type. Frames are columns in plants.png, one frame for
+// every growth stage. StageTicks is how many Update ticks a watered plant
+// stay in each stage. The last stage is ripe and has no duration.
+type Crop struct {
+	Name       string   `json:"name"`
+	Row        int      `json:"row"`
+	Frames     []int    `json:"frames"`
+	StageTicks []int    `json:"stageTicks"`
+	Icon       int      `json:"icon"`
+	SeedIcon   int      `json:"seedIcon"`
+	Yield      int      `json:"yield"`
+	SellPrice  int      `json:"sellPrice"`
+	SeedPrice  int      `json:"seedPrice"`
+	Seasons    []string `json:"seasons"`
+}
+
+type CropsJSON struct {
+	Crops  []*Crop `json:"crops"`
+	byName map[string]*Crop
+}
+
+func NewCropsJSON(filepath string) (*CropsJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var cropsJSON CropsJSON
+	err = json.Unmarshal(content, &cropsJSON)
+	if err != nil {
+		return nil, err
+	}
+	cropsJSON.byName = make(map[string]*Crop)
+	for _, c := range cropsJSON.Crops {
+		if len(c.Frames) == 0 || len(c.StageTicks) != len(c.Frames)-1 {
+			return nil, fmt.Errorf("crops: %s needs one stageTicks less than frames", c.Name)
+		}
+		cropsJSON.byName[c.Name] = c
+	}
+	return &cropsJSON, nil
+}
+
+// Get crop by name
+func (c *CropsJSON) Get(name string) (*Crop, bool) {
+	crop, ok := c.byName[name]
+	return crop, ok
+}
+
+// Stage index for a plant that has grown ticks
+func (c *Crop) Stage(ticks int) int {
+	for i, t := range c.StageTicks {
+		if ticks < t {
+			return i
+		}
+		ticks -= t
+	}
+	return len(c.StageTicks)
+}
+
+// Frame in plants.png for a plant that has grown ticks
+func (c *Crop) Frame(ticks int) int {
+	return c.Frames[c.Stage(ticks)]
+}
+
+// Ripe when the plant has reached the last stage
+func (c *Crop) Ripe(ticks int) bool {
+	return c.Stage(ticks) == len(c.StageTicks)
+}
+
+// InSeason check if the crop can grow in season
+func (c *Crop) InSeason(season string) bool {
+	return slices.Contains(c.Seasons, season)
+}
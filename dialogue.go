@@ -0,0 +1,229 @@
+package main
+
+import (
+	"image"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/eklownr/gorpg/dialogues"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	talkReach         = imgSize
+	dialogueCharTicks = 2   // ticks for every letter of the typewriter text
+	dialogueBoxW      = 316 // the frame in InfoBox.png
+	dialogueBoxH      = 60
+	dialogueX         = (screenWidth - dialogueBoxW) / 2
+	dialogueY         = screenHeight - dialogueBoxH - 4
+	dialogueLine      = 46 // letters in a text line
+	dialogueRow       = 14
+	portraitSize      = 40
+)
+
+// Conversation is the dialogue the Player is in
+type Conversation struct {
+	dialogue *dialogues.Dialogue
+	node     *dialogues.Node
+	text     []rune
+	shown    int // letters shown by the typewriter
+	ticks    int
+	choices  []dialogues.Choice // choices that pass their rules
+	sel      int
+}
+
+// someone the Player can talk to: an interior villager, the budda or a worker
+func (g *Game) nearSpeaker() string {
+	feet := Point{g.Player.pos.x + imgSize/2, g.Player.pos.y + imgSize*3/4}
+	near := func(p Point) bool { return math.Hypot(p.x-feet.x, p.y-feet.y) < talkReach }
+	if g.inside != nil {
+		if npc := g.nearNPC(); npc != nil {
+			return npc.name
+		}
+		return ""
+	}
+	for _, house := range g.house {
+		if house.variety == "budda" && house.active && near(Point{house.pos.x + 16, house.pos.y + 24}) {
+			return "budda"
+		}
+	}
+	for _, w := range g.workers {
+		if w.active && near(Point{w.pos.x + imgSize/2, w.pos.y + imgSize*3/4}) {
+			return "worker"
+		}
+	}
+	return ""
+}
+
+// start the dialogue of name, false if name has nothing to say
+func (g *Game) talkTo(name string) bool {
+	d, ok := g.dialogues.Get(name)
+	if !ok {
+		return false
+	}
+	first := d.First(g.stats())
+	if first == nil {
+		return false
+	}
+	g.conversation = &Conversation{dialogue: d}
+	g.showNode(first)
+	playSound(audioFx)
+	return true
+}
+
+// show node and run its effects. A nil node end the dialogue, and so does a
+// node with a trade the Player can't pay. Choices to such nodes are hidden
+func (g *Game) showNode(n *dialogues.Node) {
+	c := g.conversation
+	if n == nil {
+		g.conversation = nil
+		return
+	}
+	if !g.canPay(n.Effects) {
+		Publish(g, ActionFailed{"Not enough items", ""})
+		g.conversation = nil
+		return
+	}
+	c.node, c.text, c.shown, c.ticks, c.sel = n, []rune(n.Text), 0, 0, 0
+	stats := g.stats()
+	c.choices = slices.DeleteFunc(slices.Clone(n.Choices), func(ch dialogues.Choice) bool {
+		next := c.dialogue.Node(ch.Next)
+		return !dialogues.Pass(ch.If, stats) || next != nil && !g.canPay(next.Effects)
+	})
+	for _, e := range n.Effects {
+		g.dialogueEffect(e)
+	}
+}
+
+// the Player has every item the effects take
+func (g *Game) canPay(effects []dialogues.Effect) bool {
+	take := make(map[string]int)
+	for _, e := range effects {
+		if e.Take != "" {
+			take[e.Take] += max(e.Count, 1)
+		}
+	}
+	for item, n := range take {
+		if g.Player.inv.Count(item) < n {
+			return false
+		}
+	}
+	return true
+}
+
+// give or take items, start quests and set flags. An item is only given
+// when the item to take was taken
+func (g *Game) dialogueEffect(e dialogues.Effect) {
+	count := max(e.Count, 1)
+	if e.Take != "" {
+		if taken := g.Player.inv.Remove(e.Take, count); taken < count {
+			g.Player.inv.Add(e.Take, taken)
+			return
+		}
+	}
+	if e.Flag != "" {
+		g.flags[e.Flag] = true
+	}
+	if e.Give != "" {
+		g.Player.inv.Add(e.Give, count)
+		playSound(audioCoin)
+	}
+	if e.Quest != "" {
+		g.startQuest(e.Quest)
+	}
+}
+
+// typewriter text, choices and keys. The interact key, Enter or Space show
+// the whole text, then pick the choice or go to the next node. Escape end the dialogue
+func (g *Game) updateDialogue() {
+	c := g.conversation
+	if c.shown < len(c.text) {
+		c.ticks++
+		if c.ticks >= dialogueCharTicks {
+			c.ticks = 0
+			c.shown++
+		}
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || gamepadJustPressed(ebiten.StandardGamepadButtonRightRight):
+		g.conversation = nil
+	case menuKey(actUp, ebiten.KeyArrowUp, ebiten.StandardGamepadButtonLeftTop) && len(c.choices) > 0:
+		c.sel = (c.sel + len(c.choices) - 1) % len(c.choices)
+	case menuKey(actDown, ebiten.KeyArrowDown, ebiten.StandardGamepadButtonLeftBottom) && len(c.choices) > 0:
+		c.sel = (c.sel + 1) % len(c.choices)
+	case keyJustPressed(actInteract) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		inpututil.IsKeyJustPressed(ebiten.KeySpace) || gamepadJustPressed(ebiten.StandardGamepadButtonRightBottom):
+		if c.shown < len(c.text) {
+			c.shown = len(c.text)
+		} else if len(c.choices) > 0 {
+			g.showNode(c.dialogue.Node(c.choices[c.sel].Next))
+		} else {
+			g.showNode(c.dialogue.Node(c.node.Next))
+		}
+	}
+}
+
+// split text in lines of at most n letters
+func wrapText(text string, n int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > n {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// draw the dialogue in the InfoBox frame with the portrait, and the choices above it
+func (g *Game) drawDialogue(screen *ebiten.Image) {
+	c := g.conversation
+	if c == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(dialogueX, dialogueY)
+	screen.DrawImage(subImage(g.infoBoxSpite.img, image.Rect(0, 0, dialogueBoxW, dialogueBoxH)), op)
+
+	portrait := c.dialogue.Portrait
+	if c.node.Portrait != nil {
+		portrait = *c.node.Portrait
+	}
+	r := portrait.Bounds()
+	scale := float64(portraitSize) / float64(max(r.Dx(), r.Dy()))
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(dialogueX+10, dialogueY+10)
+	screen.DrawImage(subImage(g.portraits[portrait.Image], r), op)
+
+	x := float64(dialogueX + portraitSize + 18)
+	addTextAt(screen, 10, c.node.Speaker, brown, x, dialogueY+6)
+	for i, line := range wrapText(string(c.text[:c.shown]), dialogueLine) {
+		addTextAt(screen, 10, line, black, x, dialogueY+20+float64(i*12))
+	}
+	if c.shown < len(c.text) || len(c.choices) == 0 {
+		return
+	}
+	h := float32(len(c.choices)*dialogueRow + 8)
+	y := float32(dialogueY) - h - 4
+	vector.DrawFilledRect(screen, dialogueX, y, dialogueBoxW, h, blue_transp, true)
+	for i, ch := range c.choices {
+		col := white
+		if i == c.sel {
+			vector.DrawFilledRect(screen, dialogueX+2, y+4+float32(i*dialogueRow), dialogueBoxW-4, dialogueRow, blue_rect, true)
+			col = yellow
+		}
+		addTextAt(screen, 10, ch.Text, col, dialogueX+8, float64(y)+5+float64(i*dialogueRow))
+	}
+}
@@ -0,0 +1,167 @@
+package dialogues
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/eklownr/gorpg/tiers"
+)
+
+// Portrait is a part of a sprite image, drawn next to the text
+type Portrait struct {
+	Image string `json:"image"` // sprite image path
+	Rect  [4]int `json:"rect"`  // x0, y0, x1, y1 in the image
+}
+
+// Branch go to Node when all rules in If pass, like the tier rules
+type Branch struct {
+	Node string       `json:"node"`
+	If   []tiers.Rule `json:"if"`
+}
+
+// Choice is an answer the Player can pick. Choices that don't pass If are hidden
+type Choice struct {
+	Text string       `json:"text"`
+	Next string       `json:"next"` // "" end the dialogue
+	If   []tiers.Rule `json:"if"`
+}
+
+// Effect happen when the node is shown
+type Effect struct {
+	Give  string `json:"give"` // item the Player get
+	Take  string `json:"take"` // item the Player lose
+	Count int    `json:"count"`
+	Quest string `json:"quest"` // quest to start
+	Flag  string `json:"flag"`  // flag to set, the stat "flag.<name>" is 1 after
+}
+
+// Node is one line of the dialogue
+type Node struct {
+	ID       string    `json:"id"`
+	Speaker  string    `json:"speaker"`
+	Text     string    `json:"text"`
+	Portrait *Portrait `json:"portrait"` // nil use the dialogue portrait
+	Effects  []Effect  `json:"effects"`
+	Choices  []Choice  `json:"choices"`
+	Next     string    `json:"next"` // node after the text when there are no choices, "" end
+}
+
+// Dialogue is a conversation. It start at the first Start branch that pass
+type Dialogue struct {
+	Name     string   `json:"name"` // who speak: "budda", "worker" or a villager name
+	Portrait Portrait `json:"portrait"`
+	Start    []Branch `json:"start"`
+	Nodes    []*Node  `json:"nodes"`
+	byID     map[string]*Node
+}
+
+type DialoguesJSON struct {
+	Dialogues []*Dialogue `json:"dialogues"`
+	byName    map[string]*Dialogue
+}
+
+func NewDialoguesJSON(filepath string) (*DialoguesJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var dialoguesJSON DialoguesJSON
+	err = json.Unmarshal(content, &dialoguesJSON)
+	if err != nil {
+		return nil, err
+	}
+	dialoguesJSON.byName = make(map[string]*Dialogue)
+	for _, d := range dialoguesJSON.Dialogues {
+		if dialoguesJSON.byName[d.Name] != nil {
+			return nil, fmt.Errorf("dialogues: %s is defined twice", d.Name)
+		}
+		if err := d.index(); err != nil {
+			return nil, fmt.Errorf("dialogues: %s: %w", d.Name, err)
+		}
+		dialoguesJSON.byName[d.Name] = d
+	}
+	return &dialoguesJSON, nil
+}
+
+// index the nodes and check that every branch and choice go to a node
+func (d *Dialogue) index() error {
+	d.byID = make(map[string]*Node)
+	for _, n := range d.Nodes {
+		if d.byID[n.ID] != nil {
+			return fmt.Errorf("node %s is defined twice", n.ID)
+		}
+		d.byID[n.ID] = n
+	}
+	if len(d.Start) == 0 {
+		return fmt.Errorf("no start node")
+	}
+	for _, b := range d.Start {
+		if d.byID[b.Node] == nil {
+			return fmt.Errorf("unknown start node %q", b.Node)
+		}
+		if err := validate(b.If); err != nil {
+			return err
+		}
+	}
+	for _, n := range d.Nodes {
+		if n.Next != "" && d.byID[n.Next] == nil {
+			return fmt.Errorf("node %s: unknown next node %q", n.ID, n.Next)
+		}
+		for _, c := range n.Choices {
+			if c.Next != "" && d.byID[c.Next] == nil {
+				return fmt.Errorf("node %s: unknown choice node %q", n.ID, c.Next)
+			}
+			if err := validate(c.If); err != nil {
+				return fmt.Errorf("node %s: %w", n.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validate(rules []tiers.Rule) error {
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get dialogue by name
+func (d *DialoguesJSON) Get(name string) (*Dialogue, bool) {
+	dialogue, ok := d.byName[name]
+	return dialogue, ok
+}
+
+// Node by id, nil if there is no such node
+func (d *Dialogue) Node(id string) *Node {
+	return d.byID[id]
+}
+
+// First node with a start branch that pass, nil if none pass
+func (d *Dialogue) First(stats map[string]int) *Node {
+	for _, b := range d.Start {
+		if Pass(b.If, stats) {
+			return d.byID[b.Node]
+		}
+	}
+	return nil
+}
+
+// Pass when all rules pass
+func Pass(rules []tiers.Rule, stats map[string]int) bool {
+	for _, r := range rules {
+		if !r.Check(stats) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds of the portrait in the image
+func (p Portrait) Bounds() image.Rectangle {
+	return image.Rect(p.Rect[0], p.Rect[1], p.Rect[2], p.Rect[3])
+}
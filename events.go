@@ -0,0 +1,132 @@
+package main
+
+import "reflect"
+
+// EventBus call the handlers that subscribed to an event type when an event
+// of that type is published. Handlers get the Game as an argument instead of
+// keeping a pointer to it, restart copy a new Game over the old one
+type EventBus struct {
+	handlers map[reflect.Type][]func(*Game, any)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[reflect.Type][]func(*Game, any))}
+}
+
+// Subscribe fn to events of type E
+func Subscribe[E any](b *EventBus, fn func(*Game, E)) {
+	t := reflect.TypeFor[E]()
+	b.handlers[t] = append(b.handlers[t], func(g *Game, e any) { fn(g, e.(E)) })
+}
+
+// Publish e to the handlers of its type, in the order they subscribed
+func Publish[E any](g *Game, e E) {
+	for _, fn := range g.events.handlers[reflect.TypeFor[E]()] {
+		fn(g, e)
+	}
+}
+
+// ItemPicked is an item the Player picked up or harvested at Pos. Coins are CoinEarned
+type ItemPicked struct {
+	Item  string
+	Count int
+	Pos   Point
+}
+
+// CoinEarned is coins to the wallet, picked up or from a sale
+type CoinEarned struct {
+	Count int
+	Pos   Point
+}
+
+// ItemSold is an item sold to the budda
+type ItemSold struct {
+	Item  string
+	Price int
+}
+
+// ChickenDelivered is a chicken the Player brought to the chicken house
+type ChickenDelivered struct{}
+
+// ItemDelivered is an item the Player delivered to a construction site
+type ItemDelivered struct {
+	Item string
+	Site *Building
+}
+
+// BuildingFinished is a construction site that is done
+type BuildingFinished struct {
+	Building *Building
+}
+
+// SceneChanged is a new scene, interiorScene when the Player walk into a house
+type SceneChanged struct {
+	From, To int
+}
+
+// WorkerHired is a worker with a new contract
+type WorkerHired struct {
+	Worker *Characters
+}
+
+// WorkerPaid is wages paid to a worker
+type WorkerPaid struct {
+	Worker *Characters
+	Coins  int
+}
+
+// TierReached is the next village tier
+type TierReached struct {
+	Tier int
+}
+
+// QuestStarted is a quest added to the quest log
+type QuestStarted struct {
+	Quest *Quest
+}
+
+// QuestCompleted is a quest with all objectives done
+type QuestCompleted struct {
+	Quest *Quest
+}
+
+// sounds for the pick ups, other items play audioFx
+var pickSounds = map[string][]byte{
+	eggItem:   audioSecret,
+	chestItem: audioChest,
+}
+
+// sounds for the game events
+func subscribeAudio(b *EventBus) {
+	Subscribe(b, func(g *Game, e ItemPicked) {
+		if sound, ok := pickSounds[e.Item]; ok {
+			playSound(sound)
+		} else {
+			playSound(audioFx)
+		}
+	})
+	Subscribe(b, func(g *Game, e CoinEarned) { playSound(audioCoin) })
+	Subscribe(b, func(g *Game, e ChickenDelivered) { playSound(audioFx) })
+	Subscribe(b, func(g *Game, e ItemDelivered) { playSound(audioCoin) })
+	Subscribe(b, func(g *Game, e BuildingFinished) { playSound(audioSecret) })
+	Subscribe(b, func(g *Game, e WorkerHired) { playSound(audioCoin) })
+	Subscribe(b, func(g *Game, e WorkerPaid) { playSound(audioCoin) })
+	Subscribe(b, func(g *Game, e TierReached) { playSound(audioSecret) })
+	Subscribe(b, func(g *Game, e QuestStarted) { playSound(audioSecret) })
+	Subscribe(b, func(g *Game, e QuestCompleted) { playSound(audioSecret) })
+	Subscribe(b, func(g *Game, e SceneChanged) {
+		if e.From == interiorScene || e.To == interiorScene { // door
+			playSound(audioFx)
+		}
+	})
+}
+
+// go to scene, the Player walked over the border, pressed a scene key or went through a door
+func (g *Game) setScene(scene int) {
+	if scene == g.scene {
+		return
+	}
+	from := g.scene
+	g.scene = scene
+	Publish(g, SceneChanged{from, scene})
+}
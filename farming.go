@@ -0,0 +1,176 @@
+package main
+
+import (
+	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	farmLayer  = "farmland" // tile layer on top of the farm tilemap
+	tileDry    = 189        // dry farmland in tileset_floor.png
+	tileWet    = 343        // watered farmland in tileset_floor.png
+	fallowDays = 3          // empty farmland turn back to grass
+)
+
+// tile x,y under the Player feet
+func (g *Game) playerTile() (int, int) {
+	x := g.Player.pos.x + imgSize/2
+	y := g.Player.pos.y + imgSize*3/4
+	return int(x) / tileSize, int(y) / tileSize
+}
+
+// T key till the grass under the Player into farmland
+func (g *Game) tillKey() {
+	layer := g.farmJSON.Layer(farmLayer)
+	x, y := g.playerTile()
+	if g.scene != 0 || !layer.Inside(x, y) || layer.Tile(x, y) != 0 || !g.tillable(x, y) {
+		return
+	}
+	layer.SetTile(x, y, tileDry)
+	g.tilled[y*layer.Width+x] = g.clock.Day()
+	g.puff(Point{float64(x*tileSize + 8), float64(y*tileSize + 8)})
+	playSound(audioFx)
+}
+
+// grass is only inside the background image and not under a house or building
+func (g *Game) tillable(x, y int) bool {
+	tile := image.Rect(x*tileSize, y*tileSize, (x+1)*tileSize, (y+1)*tileSize)
+	if !tile.In(image.Rect(20, 20, 620, 390)) {
+		return false
+	}
+	for _, house := range g.house {
+		r := image.Rect(int(house.pos.x), int(house.pos.y),
+			int(house.pos.x)+house.rectPos.Dx(), int(house.pos.y)+house.rectPos.Dy())
+		if house.active && r.Overlaps(tile) {
+			return false
+		}
+	}
+	for _, b := range g.buildingSites {
+		if b.footprint().Overlaps(tile) {
+			return false
+		}
+	}
+	return g.plantAt(x, y) == nil
+}
+
+// P key plant the seed in the selected hotbar slot on tilled farmland, if the crop is in season
+func (g *Game) plantKey() {
+	layer := g.farmJSON.Layer(farmLayer)
+	x, y := g.playerTile()
+	if g.scene != 0 || layer.Tile(x, y) == 0 || g.plantAt(x, y) != nil {
+		return
+	}
+	seed := g.activeItem().Item
+	crop, ok := seedCrop(seed)
+	if c, known := g.crops.Get(crop); !ok || !known || !c.InSeason(g.clock.SeasonName()) {
+		return
+	}
+	if g.Player.inv.Remove(seed, 1) == 0 {
+		return
+	}
+	g.plants = append(g.plants, g.newPlant(crop, Point{float64(x * tileSize), float64(y * tileSize)}))
+	g.tilled[y*layer.Width+x] = g.clock.Day()
+	playSound(audioFx)
+}
+
+// W key water the farmland under the Player
+func (g *Game) waterKey() {
+	layer := g.farmJSON.Layer(farmLayer)
+	x, y := g.playerTile()
+	if g.scene != 0 || layer.Tile(x, y) != tileDry {
+		return
+	}
+	layer.SetTile(x, y, tileWet)
+	g.tilled[y*layer.Width+x] = g.clock.Day()
+	g.puff(Point{float64(x*tileSize + 8), float64(y*tileSize + 8)})
+	playSound(audioFx)
+}
+
+// new growing plant of variety at pos
+func (g *Game) newPlant(variety string, pos Point) *Objects {
+	crop, _ := g.crops.Get(variety)
+	return &Objects{
+		Sprite: &Sprite{
+			img:     g.plantImg,
+			pos:     pos,
+			rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
+			active:  true,
+			frame:   crop.Frames[0],
+		},
+		variety: variety,
+	}
+}
+
+// plant on tile x,y, planted by the Player or on a worker field
+func (g *Game) plantAt(x, y int) *Objects {
+	tile := image.Rect(x*tileSize, y*tileSize, (x+1)*tileSize, (y+1)*tileSize)
+	for _, plant := range g.plants {
+		r := image.Rect(int(plant.pos.x), int(plant.pos.y), int(plant.pos.x)+plant.rectPos.Dx(), int(plant.pos.y)+plant.rectPos.Dy())
+		if r.Overlaps(tile) {
+			return plant
+		}
+	}
+	return nil
+}
+
+// plants planted by the Player grow when the farmland is wet
+func (g *Game) waterFromTiles() {
+	layer := g.farmJSON.Layer(farmLayer)
+	for _, plant := range g.plants {
+		if plant.worker != nil {
+			continue // worker water the field
+		}
+		plant.water = 0
+		if layer.Tile(int(plant.pos.x)/tileSize, int(plant.pos.y)/tileSize) == tileWet {
+			plant.water = 1
+		}
+	}
+}
+
+// harvested plants planted by the Player are gone, the farmland stay
+func (g *Game) removeHarvested() {
+	g.plants = slices.DeleteFunc(g.plants, func(plant *Objects) bool {
+		return plant.worker == nil && plant.picked
+	})
+}
+
+// wet farmland dry out, unused farmland turn back to grass. New market prices
+func (g *Game) newDay() {
+	g.market.newDay()
+	layer := g.farmJSON.Layer(farmLayer)
+	for i, id := range layer.Data {
+		x, y := i%layer.Width, i/layer.Width
+		if id == tileWet {
+			layer.Data[i] = tileDry
+		}
+		if id != 0 && g.plantAt(x, y) == nil && g.clock.Day()-g.tilled[i] >= fallowDays {
+			layer.Data[i] = 0
+			delete(g.tilled, i)
+		}
+	}
+}
+
+// draw farmland tile layer on top of the farm background
+func (g *Game) drawFarmland(screen *ebiten.Image) {
+	layer := g.farmJSON.Layer(farmLayer)
+	op := &ebiten.DrawImageOptions{}
+	for index, id := range layer.Data {
+		if id == 0 {
+			continue
+		}
+		x := index % layer.Width * tileSize
+		y := index / layer.Width * tileSize
+
+		srcX := (id - 1) % 22 * tileSize
+		srcY := (id - 1) / 22 * tileSize
+
+		op.GeoM.Translate(float64(x), float64(y))
+		screen.DrawImage(
+			subImage(g.tilesetSeasons[g.clock.Season()], image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)),
+			op,
+		)
+		op.GeoM.Reset()
+	}
+}
@@ -11,18 +11,21 @@ import (
 
 	"time"
 
-  "github.com/eklownr/gorpg/tilemaps"
+	"github.com/eklownr/gorpg/achievements"
+	"github.com/eklownr/gorpg/buildings"
+	"github.com/eklownr/gorpg/crops"
+	"github.com/eklownr/gorpg/dialogues"
+	"github.com/eklownr/gorpg/quests"
+	"github.com/eklownr/gorpg/tiers"
+	"github.com/eklownr/gorpg/tilemaps"
 
 	"github.com/ebitenui/ebitenui/widget"
 	"github.com/hajimehoshi/ebiten/examples/resources/fonts"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
-	"golang.org/x/image/font/gofont/goregular"
 )
 
 const (
@@ -31,6 +34,7 @@ const (
 	imgSize       = 48
 	SPEED         = time.Second / 4
 	houseTileSize = 64
+	waterTicks    = 60 * 20 // a watered plant grow for 20 sec
 	SampleRate    = 44100
 	wheat         = "wheat"
 	tomato        = "tomato"
@@ -72,14 +76,14 @@ var (
 	orange          = color.RGBA{180, 160, 0, 255}
 	white           = color.RGBA{255, 255, 255, 255}
 	black           = color.RGBA{0, 0, 0, 255}
+	brown           = color.RGBA{120, 80, 40, 255}
+	brown_transp    = color.RGBA{120, 80, 40, 140}
+	gray            = color.RGBA{140, 140, 140, 255}
 	gameSpeed       = SPEED
 	PlayerSpeed     = 3.0
 	diagonalSpeed   = 0.8
 	tileSize        = 16
 	mplusFaceSource *text.GoTextFaceSource
-	coin_anim       = 0
-	plant_anim      = 0
-	chicken_anim    = 0
 )
 
 type Game struct {
@@ -93,12 +97,22 @@ type Game struct {
 	buddaSpawnItems   []*Objects
 	lastUpdate        time.Time
 	tick              bool
-	fullWindow        bool
-	gamePause         bool
-	village           *ebiten.Image
-	bgImg             *ebiten.Image
+	workerPanel       bool
+	market            *Market
+	marketOpen        bool
+	inventoryOpen     bool
+	hotbarSel         int        // selected hotbar slot
+	drag              *dragStack // stack dragged with the mouse
+	chests            []*StorageChest
+	openChest         *StorageChest
+	chestImg          *ebiten.Image
+	buildings         *buildings.BuildingsJSON
+	buildingSites     []*Building              // construction sites and finished buildings
+	buildingImgs      map[string]*ebiten.Image // blueprint images by file
+	buildMode         bool
+	blueprintSel      int // selected blueprint in the build menu
+	biomImg           *ebiten.Image
 	tilemapImg        *ebiten.Image
-	tilemapImgWater   *ebiten.Image
 	plantImg          *ebiten.Image
 	workImg           *ebiten.Image
 	workerIdleImg     *ebiten.Image
@@ -107,22 +121,66 @@ type Game struct {
 	eggImg            *ebiten.Image
 	infoBoxSpite      *Sprite
 	addBottonImg      *widget.ButtonImage
-	smokeSprite       *Sprite
+	particles         *Particles
+	emitters          []*Emitter // emitters that spawn every tick
+	dust              *Emitter   // dust at the Player feet
+	lastPuff          int        // clock tick of the last smoke puff
+	smokeFx           *ParticleStyle
+	sparkleFx         *ParticleStyle
+	dustFx            *ParticleStyle
+	emberFx           *ParticleStyle
 	tilemapJSON1      *tilemaps.TilemapJSON
 	tilemapJSON2      *tilemaps.TilemapJSON
 	tilemapJSON3      *tilemaps.TilemapJSON
+	crops             *crops.CropsJSON
+	farmJSON          *tilemaps.TilemapJSON // scene 0 with the farmland layer
+	tilled            map[int]int           // farmland tile index, day it was last used
+	tiers             *tiers.TiersJSON
+	tier              int // reached village tier
+	buddaVisits       int
+	harvests          int
+	chickensDelivered int
+	coopFeed          int // grain in the coop feeder
+	clock             Clock
+	calendarOpen      bool
+	renderQueue       RenderQueue // world sprites sorted by Y
+	debugOpen         bool        // draw stats
+	hud               *HUD
+	menu              *Menu // title screen, pause menu and options
+	dialogues         *dialogues.DialoguesJSON
+	portraits         map[string]*ebiten.Image // dialogue portrait images by path
+	conversation      *Conversation            // dialogue the Player is in, nil if none
+	flags             map[string]bool          // flags set by dialogues, like seeds the budda has given
+	questsData        *quests.QuestsJSON
+	quests            []*Quest // started quests, done quests stay in the quest log
+	questLogOpen      bool
+	events            *EventBus // audio, particles, stats and quests react to game events
+	achievements      *achievements.AchievementsJSON
+	toasts            []*Toast     // notices in the HUD corner, sorted by priority
+	floats            []*FloatText // texts rising over the world
+	interiors         []*Interior  // enterable buildings
+	inside            *Interior    // building the Player is in, nil outside
+	outsideScene      int          // scene and Player position to go back to
+	outsidePos        Point
+	bgSeasons         *Seasonal // background, tileset and water tileset for every season
+	tilesetSeasons    *Seasonal
+	waterSeasons      *Seasonal
+	workersHome       bool // workers are home for the night
+	weather           *Weather
+	fires             []*Campfire
+	lightMap          *ebiten.Image // offscreen light map for the night
+	lightImg          *ebiten.Image
 	scene             int
 	exitGame          bool
 	buddaAnimCounter  int
-	buddaSpawnCounter int
+	sales             int // items sold to the budda, for the stats
 }
 type Sprite struct {
 	img          *ebiten.Image
 	pos          Point
 	prePos       Point
 	rectPos      image.Rectangle
-	rectTop      Point // Sprite amination
-	rectBot      Point // Sprite amination
+	anim         *Animator // Sprite amination
 	active       bool
 	frameCounter int
 	frame        int
@@ -130,23 +188,28 @@ type Sprite struct {
 type Characters struct {
 	*Sprite
 	Dir
-	speed         float64
-	dest          Point
-	coin          int
-	wallet        int
-	basketSize    int
-	tomatoBasket  int
-	wheatBasket   int
-	chicken       int
-	chicken_count int
-	egg           int
+	speed      float64
+	dest       Point
+	coin       int        // worker coins
+	wallet     int        // max coins for the Player
+	basketSize int        // max crops for the Player
+	inv        *Inventory // Player items: coins, crops, seeds, chicken and egg
+	contract   *Contract  // worker contract with wages
+	morale     int        // worker morale 0-100
 }
 type Objects struct {
 	*Sprite
-	variety  string
-	dest     Point
-	picked   bool
-	pickable bool
+	variety   string
+	dest      Point
+	picked    bool
+	pickable  bool
+	growTicks int         // ticks the plant has grown
+	water     int         // ticks left before the plant is dry
+	worker    *Characters // worker that work the field, nil if planted by the Player
+	hunger    int         // chicken hunger, 0 is fed
+	age       int         // ticks since the chicken hatched or the egg was laid
+	layTicks  int         // ticks since the hen laid an egg
+	roof      float32     // house roof fade, 0 is solid
 }
 type Point struct {
 	x, y float64
@@ -157,18 +220,8 @@ type Dir struct {
 
 // Idle workers faceing front animation
 func (g *Game) idleWorkers(i int) {
-	// show animation subImage
-	if g.tick {
-		g.workers[i].rectTop.x = imgSize - imgSize // 0
-		g.workers[i].rectTop.y = imgSize - imgSize // 0
-		g.workers[i].rectBot.x = imgSize           // 48
-		g.workers[i].rectBot.y = imgSize           // 48
-	} else {
-		g.workers[i].rectTop.x = imgSize
-		g.workers[i].rectTop.y = imgSize - imgSize
-		g.workers[i].rectBot.x = imgSize * 2
-		g.workers[i].rectBot.y = imgSize
-	}
+	g.workers[i].anim.Play("idle")
+	g.workers[i].anim.Update()
 }
 
 // return random point position
@@ -182,18 +235,7 @@ func randomPoint() Point {
 
 // Idle faceing front animation
 func (g *Game) idle() {
-	// show animation subImage
-	if g.tick {
-		g.Player.rectTop.x = imgSize - imgSize // 0
-		g.Player.rectTop.y = imgSize - imgSize // 0
-		g.Player.rectBot.x = imgSize           // 48
-		g.Player.rectBot.y = imgSize           // 48
-	} else {
-		g.Player.rectTop.x = imgSize
-		g.Player.rectTop.y = imgSize - imgSize
-		g.Player.rectBot.x = imgSize * 2
-		g.Player.rectBot.y = imgSize
-	}
+	g.Player.anim.Play("idle")
 }
 
 // set new position and player images(animation)
@@ -202,18 +244,7 @@ func (g *Game) dirDown() {
 		g.Player.speed = PlayerSpeed * diagonalSpeed
 	}
 	g.Player.pos.y += g.Player.speed
-	// show animation subImage
-	if g.tick {
-		g.Player.rectTop.x = imgSize * 2
-		g.Player.rectTop.y = imgSize - imgSize
-		g.Player.rectBot.x = imgSize * 3
-		g.Player.rectBot.y = imgSize
-	} else {
-		g.Player.rectTop.x = imgSize * 3
-		g.Player.rectTop.y = imgSize - imgSize
-		g.Player.rectBot.x = imgSize * 4
-		g.Player.rectBot.y = imgSize
-	}
+	g.Player.anim.Play("down")
 	g.Player.Dir.down = false
 	g.Player.Dir.right = false
 	g.Player.Dir.left = false
@@ -226,18 +257,7 @@ func (g *Game) dirUp() {
 		g.Player.speed = PlayerSpeed * diagonalSpeed
 	}
 	g.Player.pos.y -= g.Player.speed
-	// show animation subImage
-	if g.tick {
-		g.Player.rectTop.x = imgSize * 2
-		g.Player.rectTop.y = imgSize
-		g.Player.rectBot.x = imgSize * 3
-		g.Player.rectBot.y = imgSize * 2
-	} else {
-		g.Player.rectTop.x = imgSize * 3
-		g.Player.rectTop.y = imgSize
-		g.Player.rectBot.x = imgSize * 4
-		g.Player.rectBot.y = imgSize * 2
-	}
+	g.Player.anim.Play("up")
 	g.Player.Dir.up = false
 	g.Player.Dir.right = false
 	g.Player.Dir.left = false
@@ -250,18 +270,7 @@ func (g *Game) dirLeft() {
 		g.Player.speed = PlayerSpeed * diagonalSpeed
 	}
 	g.Player.pos.x -= g.Player.speed
-	// show animation subImage
-	if g.tick {
-		g.Player.rectTop.x = imgSize * 2
-		g.Player.rectTop.y = imgSize * 2
-		g.Player.rectBot.x = imgSize * 3
-		g.Player.rectBot.y = imgSize * 3
-	} else {
-		g.Player.rectTop.x = imgSize * 3
-		g.Player.rectTop.y = imgSize * 2
-		g.Player.rectBot.x = imgSize * 4
-		g.Player.rectBot.y = imgSize * 3
-	}
+	g.Player.anim.Play("left")
 	g.Player.Dir.left = false
 	g.Player.Dir.up = false
 	g.Player.Dir.down = false
@@ -274,18 +283,7 @@ func (g *Game) dirRight() {
 		g.Player.speed = PlayerSpeed * diagonalSpeed
 	}
 	g.Player.pos.x += g.Player.speed
-	// show animation subImage
-	if g.tick {
-		g.Player.rectTop.x = imgSize - imgSize
-		g.Player.rectTop.y = imgSize * 3
-		g.Player.rectBot.x = imgSize
-		g.Player.rectBot.y = imgSize * 4
-	} else {
-		g.Player.rectTop.x = imgSize * 2
-		g.Player.rectTop.y = imgSize * 3
-		g.Player.rectBot.x = imgSize * 3
-		g.Player.rectBot.y = imgSize * 4
-	}
+	g.Player.anim.Play("right")
 	g.Player.Dir.right = false
 	g.Player.Dir.up = false
 	g.Player.Dir.down = false
@@ -330,7 +328,7 @@ func (g *Game) Collision_worker_plant(worker Characters, plant Objects) bool {
 		int(plant.pos.y+imgSize/2))
 
 	if worker_position.Overlaps(plant_position) {
-		g.smokeSprite.active = true
+		g.puff(Point{plant.pos.x + 8, plant.pos.y + 8})
 		return true
 	}
 	return false
@@ -351,7 +349,7 @@ func (g *Game) Collision_Object_Caracter(obj Objects, char Characters) bool {
 		int(obj.pos.x+imgSize/2),
 		int(obj.pos.y+imgSize/2))
 
-	if obj.variety == "coin" || obj.variety == "wheat" || obj.variety == "tomato" {
+	if _, isCrop := g.crops.Get(obj.variety); isCrop || obj.variety == "coin" {
 		object_position = image.Rect(
 			int(obj.pos.x-imgSize/4+10),
 			int(obj.pos.y-imgSize/4+10),
@@ -365,17 +363,17 @@ func (g *Game) Collision_Object_Caracter(obj Objects, char Characters) bool {
 			int(obj.pos.x+imgSize/2),
 			int(obj.pos.y+imgSize/2))
 	}
-	if obj.variety == "house" {
+	if obj.variety == "house" { // only the walls, the Player can walk behind the roof
 		object_position = image.Rect(
 			int(obj.pos.x),
-			int(obj.pos.y),
+			int(obj.pos.y+imgSize/2),
 			int(obj.pos.x+houseTileSize-10),
 			int(obj.pos.y+imgSize-10))
 	}
 	if obj.variety == "small_house" {
 		object_position = image.Rect(
 			int(obj.pos.x),
-			int(obj.pos.y),
+			int(obj.pos.y+imgSize/2),
 			int(obj.pos.x+imgSize-10),
 			int(obj.pos.y+imgSize-10))
 	}
@@ -404,94 +402,34 @@ func (g *Game) buddaCollision() {
 	g.Player.pos.y = screenHeight/2 + 60
 	// playSound
 	playSound(audioFx)
-	// Check if player has Tomatos and have a big wallet for the coins
-	if g.Player.tomatoBasket > 0 && g.Player.coin < g.Player.wallet {
-		g.Player.tomatoBasket--
-		g.Player.coin += 2
-		playSound(audioCoin)
-		g.buddaSpawnCounter++ // count upp level
-	}
-	if g.Player.wheatBasket > 0 && g.Player.coin < g.Player.wallet {
-		g.Player.wheatBasket--
-		g.Player.coin += 1
-		playSound(audioCoin)
-		g.buddaSpawnCounter++ // count upp level
-	}
-	if g.Player.egg > 0 {
-		g.Player.egg--
-		g.eggs[0].active = false
-		g.buddaSpawnItems[chest].active = true // show chest TEST
-		g.buddaSpawnItems[chest].pickable = true
-		g.buddaSpawnItems[chest].picked = false
-		g.coins[0].active = true
-		g.coins[0].picked = false
-	}
-	if g.buddaSpawnCounter > 3 {
-		// add action: spawn workers
-		g.workers[2].active = true
-		if g.buddaSpawnCounter > 4 {
-			g.workers[3].active = true
-		}
-		if g.buddaSpawnCounter > 5 {
-			g.workers[4].active = true
-		}
-		if g.buddaSpawnCounter > 6 {
-			g.workers[5].active = true
-		}
-		if g.buddaSpawnCounter > 7 {
-			g.workers[6].active = true
-		}
-		if g.buddaSpawnCounter > 8 {
-			g.workers[7].active = true
-		}
-		if g.buddaSpawnCounter > 9 {
-			g.workers[8].active = true
-		}
-		if g.buddaSpawnCounter > 10 {
-			g.scene = 1 // from scene 0 to 1
-			g.workers[9].active = true
-			for _, house := range g.house {
-				house.active = false
-				if house.variety == "new_house" ||
-					house.variety == "new_house_small" ||
-					house.variety == "chicken_house" {
-					house.active = true
-				}
-			}
-		}
-	}
-	g.workers[0].active = true // activate 2 workers at start
-	g.workers[1].active = true
-
-	// dopp all item if to greedy
-	if g.Player.tomatoBasket == 5 || g.Player.wheatBasket == 5 || g.Player.coin == 5 {
-		g.Player.tomatoBasket = 0
-		g.Player.wheatBasket = 0
-		g.Player.coin = 0
-	}
+	// trade crops, seeds and chests in the budda market
+	g.openMarket()
+	g.buddaVisits++ // workers, scene and buildings unlock with the village tiers
 }
 
-// Move Workers to dest pos
+// Move Workers to dest pos. Walking speed depends on morale
 func (g *Game) moveCharacters(c *Characters) {
 	if c.pos != c.dest {
 		c.img = g.workerIdleImg
-		if c.pos.x < c.dest.x {
-			c.pos.x++
-		}
-		if c.pos.x > c.dest.x {
-			c.pos.x--
-		}
-		if c.pos.y < c.dest.y {
-			c.pos.y++
-		}
-		if c.pos.y > c.dest.y {
-			c.pos.y--
-		}
+		speed := c.walkSpeed()
+		c.pos.x = stepTo(c.pos.x, c.dest.x, speed)
+		c.pos.y = stepTo(c.pos.y, c.dest.y, speed)
 	} else {
 		c.img = g.workImg
 	}
 }
 
+// move from value to dest, but never past dest
+func stepTo(from, dest, speed float64) float64 {
+	if from < dest {
+		return min(from+speed, dest)
+	}
+	if from > dest {
+		return max(from-speed, dest)
+	}
+	return from
+}
+
 // check before moving chicken
 func (g *Game) checkChickenMovment(c *Objects) {
 	// chicken is running free and Player can pick it up
@@ -528,108 +466,133 @@ func (g *Game) moveChickenToDest(c *Objects) {
 	}
 }
 
-// check Animation tick every 60 FPS
+// grow plant one tick if it has water. Frame and pickable from the crop stages
 func (g *Game) plantFrameAnim(plant *Objects) {
-	var speed = 120 // wait two sec to next interation
-	if plant.frameCounter < speed*5 {
-		plant.frameCounter++
-	}
-	if plant.frameCounter < speed {
-		plant.frame = 1
-	} else if plant.frameCounter < speed*2 {
-		plant.frame = 2
-	} else if plant.frameCounter < speed*3 {
-		plant.frame = 3
-	} else if plant.frameCounter < speed*4 {
-		plant.frame = 4
-	} else if plant.frameCounter < speed*5 {
-		plant.frame = 5
-		plant.pickable = true
+	crop, ok := g.crops.Get(plant.variety)
+	if !ok {
+		return
 	}
+	if plant.water > 0 {
+		plant.water--
+		plant.growTicks++
+	}
+	plant.frame = crop.Frame(plant.growTicks)
+	plant.pickable = crop.Ripe(plant.growTicks)
 }
 
-// animation run once, when it's dune you can pick it.
-func (g *Game) updateFourFrameAnimOnce(obj *Objects) {
-	var speed = 120 // wait two sec to next interation
-	if obj.frameCounter < speed*5 {
-		obj.frameCounter++
-	}
-	if obj.frameCounter < speed {
-		obj.frame = 1
-	} else if obj.frameCounter < speed*2 {
-		obj.frame = 2
-	} else if obj.frameCounter < speed*3 {
-		obj.frame = 3
-	} else if obj.frameCounter < speed*4 {
-		obj.frame = 4
-		obj.pickable = true
-	}
+// plant is growing until it's ripe, only in the crop seasons
+func (g *Game) plantGrowing(plant *Objects) bool {
+	crop, ok := g.crops.Get(plant.variety)
+	return ok && plant.active && !crop.Ripe(plant.growTicks) && crop.InSeason(g.clock.SeasonName())
 }
 
 // ////////// Update:  Collision, Movement, Anim_frame, Anim_tick. ////////// //
 func (g *Game) Update() error {
+	// closing the window quit like the "q" key
+	if ebiten.IsWindowBeingClosed() {
+		g.quitGame()
+	}
 	// Exit game with "q" key
 	if g.exitGame {
 		return ebiten.Termination
 	}
 
+	// title screen, pause menu and options, the game wait
+	if g.menu.screen != menuNone {
+		g.updateHUD()
+		g.updateMenu()
+		return nil
+	}
+
+	// talking, the game wait
+	if g.conversation != nil {
+		g.animTick()
+		g.updateDialogue()
+		g.updateToasts()
+		return nil
+	}
+
+	// trading with the budda, the game wait
+	if g.marketOpen {
+		g.animTick()
+		g.updateMarket()
+		g.updateToasts()
+		return nil
+	}
+
 	g.Player.prePos = g.Player.pos // save old position before readKeys()
 	g.readKeys()                   // read keys and move player
-	g.coin_animation()
+	g.Player.anim.Update()
+	g.updateProfile() // play time and distance walked
+	for _, coin := range g.coins {
+		coin.anim.Update()
+	}
 
 	////////////////////////////////////r
 	// check Animation tick every 60 FPS. 2 values On or Off
 	g.animTick()
 
-	// pause all Update()
-	if g.gamePause {
-		return nil
-	}
+	// coins, basket and progress in the HUD
+	g.updateHUD()
+
+	g.updateInventory()
+	g.quickTransfer()
+	g.updateChests()
+	g.updateBuildMenu()
+	g.updateBuildings()
+	g.updateProgress()
+	g.updateQuests()
 
 	// Chicken walk animation. And move chicken to random destination, Collision
 	for _, chicken := range g.chickens {
-		chicken.frame = g.fourTickAnim(chicken.frame)
+		if !chicken.active {
+			continue // carried by the Player
+		}
+		chicken.anim.Update()
 		g.checkChickenMovment(chicken)
 		// if chicken reached dest, set new dest
 		if g.checkCollision(chicken.pos, chicken.dest) && chicken.pickable {
-			chicken.dest = randomPoint()
+			chicken.dest = g.chickenDest()
 		}
 		// chicken in the chickenhouse. Set new dest
 		if g.checkCollision(chicken.pos, chicken.dest) && !chicken.pickable && chicken.picked {
-			// set random pos around the chickenhouse
-			pos := randomPoint()
-			if pos.x <= 500 {
-				pos.x = 500
-			} else if pos.x >= screenWidth-20 {
-				pos.x = screenWidth - 20
-			}
-			if pos.y <= screenHeight/2-50 {
-				pos.y = screenHeight/2 - 50
-			} else if pos.y >= screenHeight-100 {
-				pos.y = screenHeight - 100
-			}
-			chicken.dest = pos
+			chicken.dest = coopPoint() // set random pos around the chickenhouse
 		}
 	}
+	// hunger, egg laying, hatching and chicks growing up
+	g.updateChickens()
 
-	// plants animation
+	// days go by and farmland dry out
+	g.updateClock()
+	g.weather.update()
+	g.updateFires()
+	g.waterFromTiles()
+
+	// plants animation. Watered plants grow as fast as the worker is productive
 	for _, plant := range g.plants {
-		if plant.active && plant.frame < 5 {
+		if g.plantGrowing(plant) && (plant.worker == nil || plant.worker.productive()) {
 			g.plantFrameAnim(plant)
 		}
 	}
 	// TEST Move workers to new dest pos for every new scene
 	for i, w := range g.workers { // Idle animation for all workers
 		g.idleWorkers(i)
+		g.updateContract(w)
 		g.moveCharacters(g.workers[i])
 
-		if w.coin > 0 { // TEST
+		if w.working() && !g.workersHome { // worker with a contract is working the field
 			w.dest = g.plants[i].pos
 			w.img = g.workImg
 			g.plants[i].picked = false
 			g.plants[i].active = true
+			if w.pos == w.dest { // worker water the plant
+				g.plants[i].water = waterTicks
+			}
+		}
+		if w.working() && !g.workersHome && g.plants[i].pickable { // field is ripe, help the builders
+			g.helpBuild(w, i)
 		}
-		if g.plants[i].picked {
+		if (g.plants[i].picked || g.workersHome) && w.active { // go home after the harvest and at dusk
 			w.img = g.workerIdleImg
 			g.workers[i].dest = Point{200 + (float64(i) * 30), 90}
 		}
@@ -647,16 +610,24 @@ func (g *Game) Update() error {
 
 	}
 
+	// inside a building only the room is updated
+	if g.inside != nil {
+		g.updateInterior()
+		g.updateParticles()
+		g.updateToasts()
+		return nil
+	}
+
 	// Player border collision - Go to next sceen
 	if g.Player.pos.x < 0-imgSize/2 {
 		g.Player.pos.x = screenWidth - imgSize/2
 		if g.scene > 0 {
-			g.scene--
+			g.setScene(g.scene - 1)
 		}
 	} else if g.Player.pos.x > screenWidth-imgSize/2 {
 		g.Player.pos.x = 0 - imgSize/2
 		if g.scene < 3 {
-			g.scene++
+			g.setScene(g.scene + 1)
 		}
 	} else if g.Player.pos.y < 0-imgSize/2 {
 		g.Player.pos.y = screenHeight - imgSize/2
@@ -666,16 +637,12 @@ func (g *Game) Update() error {
 	//Player collide with []workers
 	for i := range g.workers {
 		if g.Collision_Character_Character(*g.workers[i], *g.Player) {
-			if g.Player.coin > 0 && g.workers[i].active { // have coin and worker is active
-				if g.workers[i].coin < 1 { // take only one coin
-					g.workers[i].coin++
-					g.Player.coin--
-					playSound(audioCoin)
+			if g.Player.inv.Count(coinItem) > 0 && g.workers[i].active { // have coin and worker is active
+				if g.workers[i].contract == nil {
+					g.hireWorker(g.workers[i])
+				} else {
+					g.payWorker(g.workers[i])
 				}
-				g.smokeSprite.active = true
-				// move workers to new dest
-				g.workers[i].dest = g.plants[i].pos // set worker.dest to plant.pos
-				g.moveCharacters(g.workers[i])
 			}
 		}
 	}
@@ -700,92 +667,88 @@ func (g *Game) Update() error {
 	if g.buddaAnimCounter < 0 {
 		g.budda_animation()
 	}
+	// walk into a door and go inside the house
+	if g.enterInterior() {
+		return nil
+	}
+	g.updateRoofs()
 	//Player collide with []house or budda_house or chicken_house
 	for _, house := range g.house {
 		if g.Collision_Object_Caracter(*house, *g.Player) {
 			g.Player.pos = g.Player.prePos
-			g.smokeSprite.active = true
+			g.puff(Point{g.Player.pos.x + imgSize/2, g.Player.pos.y + imgSize/2})
 			if house.variety == "budda" {
 				g.buddaCollision()
 				g.buddaAnimCounter = -60
 			}
-			if house.variety == "chicken_house" && g.Player.chicken > 0 {
-				g.Player.chicken_count++
-				g.Player.chicken--
-				playSound(audioFx)
-				if g.Player.chicken_count > 9 { // 10 chicken in the chicken_house
-					g.eggs[1].active = true
-					g.eggs[1].pickable = true
-					g.Player.chicken_count = 0 // reset counter
-					playSound(audioSecret)
-					// set all chicken free
-					for _, c := range g.chickens {
-						c.active = true
-						c.pickable = true
-						c.picked = false
-					}
-				}
+			if house.variety == "chicken_house" {
+				g.chickenHouseVisit()
 			}
 		}
 	}
 	//Player collide with []plants if active
 	for i := range g.plants {
 		if g.Collision_Object_Caracter(*g.plants[i], *g.Player) {
-			if g.plants[i].pickable && g.Player.tomatoBasket+g.Player.wheatBasket <= g.Player.basketSize {
+			crop, _ := g.crops.Get(g.plants[i].variety)
+			harvest := []ItemStack{{crop.Name, crop.Yield}}
+			if g.plants[i].worker == nil {
+				harvest = append(harvest, ItemStack{seedItem(crop.Name), 1})
+			}
+			if g.plants[i].pickable && g.basketCount() > g.Player.basketSize {
+				Publish(g, ActionFailed{"Basket full", crop.Name})
+			} else if g.plants[i].pickable && !g.Player.inv.Fits(harvest...) {
+				Publish(g, ActionFailed{"Bag full", crop.Name}) // the plant stay ripe
+			} else if g.plants[i].pickable {
 				// pick plant
-				playSound(audioFx)
-				g.smokeSprite.active = true
-				g.workers[i].coin = 0        // drop coint when plant are picked
-				g.plants[i].active = false   // active animation
-				g.plants[i].pickable = false // can be picked
-				g.plants[i].picked = true    // Is picked
-				g.plants[i].frame = 1        // set back to first anim-frame
-				g.plants[i].frameCounter = 0 // counter back to zero
-				if g.plants[i].variety == tomato {
-					g.Player.tomatoBasket++
-				} else if g.plants[i].variety == wheat {
-					g.Player.wheatBasket++
+				if g.plants[i].worker != nil {
+					g.completeCycle(g.plants[i].worker) // worker get paid for the harvest cycle
+				} else {
+					g.Player.inv.Add(seedItem(crop.Name), 1) // get the seed back
 				}
+				g.plants[i].active = false         // active animation
+				g.plants[i].pickable = false       // can be picked
+				g.plants[i].picked = true          // Is picked
+				g.plants[i].frame = crop.Frames[0] // set back to first anim-frame
+				g.plants[i].growTicks = 0          // counter back to zero
+				g.Player.inv.Add(crop.Name, crop.Yield)
+				Publish(g, ItemPicked{crop.Name, crop.Yield, Point{g.plants[i].pos.x + 8, g.plants[i].pos.y + 8}})
 			}
 		}
 	}
+	g.removeHarvested()
 	// Player collide with []coin
 	for i := range g.coins {
 		if g.Collision_Object_Caracter(*g.coins[i], *g.Player) && g.coins[i].picked == false {
-			if g.Player.coin < g.Player.wallet { // add coins to your wallet
-				g.Player.coin++
-				playSound(audioCoin)
+			if g.Player.inv.Count(coinItem) < g.Player.wallet { // add coins to your wallet
+				g.Player.inv.Add(coinItem, 1)
+				Publish(g, CoinEarned{1, g.coins[i].pos})
 				g.coins[i].picked = true
 				//				g.coins[i].pos = Point{
 				//					x: -100,
 				//					y: -100,
 				//				}
+			} else {
+				Publish(g, ActionFailed{"Wallet full", coinItem})
 			}
 		}
 	}
 	// Player collide with chicken
 	for _, chicken := range g.chickens {
-		if g.Collision_Object_Caracter(*chicken, *g.Player) && chicken.pickable {
-			g.smokeSprite.active = true
-			if g.Player.chicken < 1 { // pick one at a time
-				g.Player.chicken++
-				chicken.pickable = false
-				chicken.picked = true
-				chicken.pos = Point{550, 150}
-				chicken.dest = Point{570, 250}
-			}
+		if g.Collision_Object_Caracter(*chicken, *g.Player) && chicken.roaming() {
+			g.pickChicken(chicken)
 		}
 	}
 	// Player collide with Eggs
 	for _, egg := range g.eggs {
 		if g.Collision_Object_Caracter(*egg, *g.Player) && egg.pickable && egg.active {
-			g.smokeSprite.active = true
-			if g.Player.egg < 1 { // pick one at a time
-				g.Player.egg++
+			if g.Player.inv.Space(eggItem) > 0 {
+				g.Player.inv.Add(eggItem, 1)
 				egg.pickable = false
 				egg.picked = true
 				egg.active = false
-				playSound(audioSecret)
+				Publish(g, ItemPicked{eggItem, 1, Point{egg.pos.x + 8, egg.pos.y + 8}})
+			} else {
+				Publish(g, ActionFailed{"Bag full", eggItem})
 			}
 		}
 	}
@@ -793,11 +756,12 @@ func (g *Game) Update() error {
 	// Player collide with Chest
 	for _, c := range g.buddaSpawnItems {
 		if g.Collision_Object_Caracter(*c, *g.Player) && c.pickable && c.active {
-			g.smokeSprite.active = true
 			c.pickable = false
 			c.picked = true
 			c.active = false
-			playSound(audioChest)
+			// storage chest to place with key: e
+			g.Player.inv.Add(chestItem, 1)
+			Publish(g, ItemPicked{chestItem, 1, Point{c.pos.x + 16, c.pos.y + 16}})
 			if g.Player.wallet < 5 { // max 6 item at a time
 				g.Player.wallet++
 			}
@@ -807,27 +771,33 @@ func (g *Game) Update() error {
 		}
 	}
 
+	// smoke, sparkles, dust and embers
+	g.updateParticles()
+	g.updateToasts()
+
 	// last in Update()
 	return nil
 }
 
 // ////////// Draw function Draw all item at 60 fps ////////// //
-func (g *Game) Draw(screen *ebiten.Image) {
-	screen.Fill(dark_green) // background collor
-
+// draw background or tile layers of the scene
+func (g *Game) drawGround(screen *ebiten.Image) {
 	// 4 different sceens. Sceen 0 only a background img. 1-3 tilemaps
 	op := &ebiten.DrawImageOptions{}
+	if g.inside != nil {
+		g.drawInteriorGround(screen)
+		return
+	}
 	if g.scene == 0 {
 		///////// draw background ///////////
 		op.GeoM.Translate(20, 20)
 
 		screen.DrawImage(
-			g.bgImg.SubImage(
-				image.Rect(0, 0, 600, 370),
-			).(*ebiten.Image),
+			subImage(g.bgSeasons[g.clock.Season()], image.Rect(0, 0, 600, 370)),
 			op,
 		)
 		op.GeoM.Reset()
+		g.drawFarmland(screen)
 	}
 	if g.scene == 1 {
 		/////////// draw bg tile layers ////////////
@@ -845,7 +815,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 				op.GeoM.Translate(float64(x), float64(y))
 				screen.DrawImage(
-					g.tilemapImg.SubImage(image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)).(*ebiten.Image),
+					subImage(g.tilesetSeasons[g.clock.Season()], image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)),
 					op,
 				)
 				op.GeoM.Reset()
@@ -868,7 +838,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 				op.GeoM.Translate(float64(x), float64(y))
 				screen.DrawImage(
-					g.tilemapImg.SubImage(image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)).(*ebiten.Image),
+					subImage(g.tilesetSeasons[g.clock.Season()], image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)),
 					op,
 				)
 				op.GeoM.Reset()
@@ -891,45 +861,88 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 				op.GeoM.Translate(float64(x), float64(y))
 				screen.DrawImage(
-					g.tilemapImgWater.SubImage(image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)).(*ebiten.Image),
+					subImage(g.waterSeasons[g.clock.Season()], image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)),
 					op,
 				)
 				op.GeoM.Reset()
 			}
 		}
 	}
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	drawStats.frame()
+	screen.Fill(dark_green) // background collor
+	q := &g.renderQueue
+
+	// tiles and farmland
+	q.Add(LayerGround, 0, g.drawGround)
+
+	// inside a building, no sky and no night
+	if g.inside != nil {
+		g.queueInterior(q)
+		q.Add(LayerWorld, footY(g.Player), g.drawPlayer)
+		q.Add(LayerOverhead, 0, g.particles.Draw)
+		q.Add(LayerOverhead, 0, g.drawFloats)
+		q.Add(LayerUI, 0, g.drawInventory)
+		q.Add(LayerUI, 0, g.drawHUD)
+		q.Add(LayerUI, 0, g.drawDialogue)
+		q.Add(LayerUI, 0, g.drawToasts)
+		q.Add(LayerUI, 0, g.drawDebug)
+		q.Flush(screen, LayerUI)
+		g.drawMenu(screen)
+		return
+	}
 
 	//// draw chickens ////
-	for i := range g.chickens {
-		g.drawChicken(screen, g.chickens[i].pos, g.chickens[i].frame)
+	for _, chicken := range g.chickens {
+		if chicken.active {
+			q.Add(LayerWorld, chicken.pos.y+16, func(screen *ebiten.Image) { g.drawChicken(screen, chicken) })
+		}
 	}
 	//// draw eggs ////
 	for _, egg := range g.eggs {
 		if egg.active {
-			g.drawItem(screen, egg.pos, egg.img, Point{16, 16})
+			q.Add(LayerWorld, egg.pos.y+16, func(screen *ebiten.Image) { g.drawItem(screen, egg.pos, egg.img, Point{16, 16}) })
 		}
 	}
+	// draw storage chests placed by the Player
+	for _, c := range g.chests {
+		if c.scene == g.scene {
+			q.Add(LayerWorld, c.pos.y+16, func(screen *ebiten.Image) { g.drawChest(screen, c) })
+		}
+	}
+
+	// draw construction sites and buildings
+	g.drawBuildings(q)
+
 	// draw budda_spawn_item
 	for _, buddaItem := range g.buddaSpawnItems {
 		if buddaItem.active == true {
 			tileSize = 16
-			g.drawChestAnim(screen, buddaItem.pos, buddaItem.img, tileSize)
+			q.Add(LayerWorld, buddaItem.pos.y+16, func(screen *ebiten.Image) { g.drawChestAnim(screen, buddaItem.pos, buddaItem.img, tileSize) })
 		}
 	}
 
 	/////////// draw all HOUSES big and small  ////////////
 	for _, house := range g.house {
-		if house.active {
-			opt := &ebiten.DrawImageOptions{}
-			opt.GeoM.Translate(house.pos.x, house.pos.y) // house position x, y
-			screen.DrawImage(
-				house.img.SubImage(
-					house.rectPos,
-				).(*ebiten.Image),
-				opt,
-			)
-			opt.GeoM.Reset()
+		footY := house.pos.y + float64(house.rectPos.Dy())
+		if house.active && hasRoof(house) {
+			queueWithRoof(q, house.img, house.rectPos, house.pos, footY, house.roof) // roof fade when the Player is behind
+		} else if house.active {
+			q.Add(LayerWorld, footY, func(screen *ebiten.Image) { drawPart(screen, house.img, house.rectPos, house.pos, 1) })
+		}
+	}
+
+	// chickens and feed in the coop
+	q.Add(LayerOverhead, 0, g.drawCoop)
+
+	// campfires
+	for _, f := range g.fires {
+		if f.scene != g.scene {
+			continue
 		}
+		q.Add(LayerWorld, f.pos.y+12, func(screen *ebiten.Image) { g.drawFire(screen, f) })
 	}
 
 	/// Draw COIN at same pos as Game constructor g.coins.pos in main() ///
@@ -938,60 +951,111 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			g.coins[i].picked = true
 		}
 		if i < 2 {
-			g.drawCoin(screen, g.coins[i].pos.x, g.coins[i].pos.y, *g.coins[i], i)
+			q.Add(LayerWorld, g.coins[i].pos.y+10, func(screen *ebiten.Image) {
+				g.drawCoin(screen, g.coins[i].pos.x, g.coins[i].pos.y, *g.coins[i], i)
+			})
 		}
 	}
 
 	/// Draw WORKERS /// if active. buddaSpawnLevel diside if active
-	for i := range g.workers {
-		// draw coin carring on workers head
-		g.carry_objects(screen, g.workers[i].pos.x, g.workers[i].pos.y, g.workers[i].coin, g.coinImg, Point{10, 10})
-		// draw all workers
-		if g.workers[i].active {
-			g.drawWorker(screen, g.workers[i].pos.x, g.workers[i].pos.y, i)
-		}
+	for i, w := range g.workers {
+		q.Add(LayerWorld, footY(w), func(screen *ebiten.Image) {
+			// draw all workers
+			if w.active {
+				g.drawWorker(screen, w.pos.x, w.pos.y, i)
+			}
+			// draw coin carring on workers head
+			g.carry_objects(screen, w.pos.x, w.pos.y, w.coin, g.coinImg, Point{10, 10})
+		})
 	}
 
-	///////// draw COINS, CHICKENS and PLANTS player caring on the head. SubImg 0,0,10,10 /////////
-	g.carry_objects(screen, g.Player.pos.x, g.Player.pos.y, g.Player.egg, g.eggImg, Point{16, 16})
-	g.carry_objects(screen, g.Player.pos.x, g.Player.pos.y, g.Player.coin, g.coinImg, Point{10, 10})
-	g.carry_objects(screen, g.Player.pos.x, g.Player.pos.y, g.Player.chicken, g.chickenImg, Point{16, 16})
-	// SubImg 0,0,16,16
-	g.carry_plant(screen, g.Player.pos.x, g.Player.pos.y, g.Player.tomatoBasket, g.plantImg, tomato)
-	g.carry_plant(screen, g.Player.pos.x, g.Player.pos.y, g.Player.wheatBasket, g.plantImg, wheat)
-
 	///// Draw all plants  if active ///
-	for i := range g.plants {
-		if g.plants[i].active {
-			g.drawPlants(screen, g.plants[i].pos.x, g.plants[i].pos.y, g.plants[i].variety, g.plants[i].frame) // wheat and tomato
+	for _, plant := range g.plants {
+		if plant.active {
+			q.Add(LayerWorld, plant.pos.y+16, func(screen *ebiten.Image) {
+				g.drawPlants(screen, plant.pos.x, plant.pos.y, plant.variety, plant.frame) // row from the crop registry
+			})
 		}
 	}
 
+	///////// draw Player ///////////
+	q.Add(LayerWorld, footY(g.Player), g.drawPlayer)
+
+	// smoke, sparkles, dust and embers
+	q.Add(LayerOverhead, 0, g.particles.Draw)
+
+	// "+2" over the budda and picked items
+	q.Add(LayerOverhead, 0, g.drawFloats)
+
+	// rain and snow
+	q.Add(LayerOverhead, 0, g.drawWeather)
+	q.Flush(screen, LayerOverhead)
+
+	// day and night, lights from fire and windows
+	g.drawLighting(screen)
+	g.drawLightning(screen)
+
 	// draw infoBox. Active with key: a
-	g.drawinfoBox(screen, g.infoBoxSpite.img, g.infoBoxSpite.pos.x, g.infoBoxSpite.pos.y)
-	g.menuText(screen) // add text to infoBoxSprite
+	q.Add(LayerUI, 0, func(screen *ebiten.Image) {
+		g.drawinfoBox(screen, g.infoBoxSpite.img, g.infoBoxSpite.pos.x, g.infoBoxSpite.pos.y)
+		g.menuText(screen) // add text to infoBoxSprite
+	})
 
-	// if active
-	g.drawSmoke(screen, g.Player.pos.x, g.Player.pos.y)
+	// hotbar and bag. Select with mouse wheel or [ ]
+	q.Add(LayerUI, 0, g.drawInventory)
 
-	///////// draw Player ///////////
+	// worker contracts. Active with key: Tab
+	q.Add(LayerUI, 0, g.drawWorkerPanel)
+
+	// budda market, open when the Player visit the budda
+	q.Add(LayerUI, 0, g.drawMarket)
+
+	// build menu with blueprints. Active with key: b
+	q.Add(LayerUI, 0, g.drawBuildMenu)
+
+	// calendar with seasons. Active with key: c
+	q.Add(LayerUI, 0, g.drawCalendar)
+
+	// quest log with objectives. Active with key: o
+	q.Add(LayerUI, 0, g.drawQuestLog)
+
+	// coins, basket, eggs, village progress and clock
+	q.Add(LayerUI, 0, g.drawHUD)
+
+	// dialogue with portrait and choices. Talk with key: e
+	q.Add(LayerUI, 0, g.drawDialogue)
+
+	// notices in the corner below the HUD
+	q.Add(LayerUI, 0, g.drawToasts)
+
+	// sprite draws and batches. Active with key: F3
+	q.Add(LayerUI, 0, g.drawDebug)
+	q.Flush(screen, LayerUI)
+
+	// title screen, pause menu and options
+	g.drawMenu(screen)
+}
+
+// draw Player and the eggs, coins, chickens and crops carried on the head
+func (g *Game) drawPlayer(screen *ebiten.Image) {
 	opts := &ebiten.DrawImageOptions{}
 	opts.GeoM.Translate(g.Player.pos.x, g.Player.pos.y)
-	// amination position to Player.img.SubImage(image.Rect(0, 0, imgSize, imgSize))
+	// amination frame from the Player animator
 	screen.DrawImage(
-		g.Player.img.SubImage(
-			image.Rect(int(g.Player.rectTop.x), int(g.Player.rectTop.y), int(g.Player.rectBot.x), int(g.Player.rectBot.y)),
-		).(*ebiten.Image),
+		subImage(g.Player.img, g.Player.anim.Rect()),
 		opts,
 	)
 	/////// TEST Draw player and house collision rect
 	// vector.StrokeRect(screen, float32(g.Player.pos.x+imgSize/4),float32(g.Player.pos.y+imgSize/4),imgSize/2,imgSize/2,3.0,color.RGBA{122, 222, 0, 100},false)
 	// vector.StrokeRect(screen,float32(g.housePos.x)+float32(g.house[0].rectPos.Min.X),float32(g.housePos.y)+float32(g.house[0].rectPos.Min.Y),houseTileSize,imgSize,3.0,color.RGBA{222, 122, 0, 100},false)
 
-	// play pause sceen
-	if g.gamePause {
-		g.pause(screen)
-		return
+	///////// draw COINS, CHICKENS and PLANTS player caring on the head. SubImg 0,0,10,10 /////////
+	g.carry_objects(screen, g.Player.pos.x, g.Player.pos.y, g.Player.inv.Count(eggItem), g.eggImg, Point{16, 16})
+	g.carry_objects(screen, g.Player.pos.x, g.Player.pos.y, g.Player.inv.Count(coinItem), g.coinImg, Point{10, 10})
+	g.carry_objects(screen, g.Player.pos.x, g.Player.pos.y, g.Player.inv.Count(chickenItem), g.chickenImg, Point{16, 16})
+	// SubImg 0,0,16,16
+	for i, crop := range g.crops.Crops {
+		g.carry_plant(screen, g.Player.pos.x, g.Player.pos.y, g.Player.inv.Count(crop.Name), g.plantImg, crop, i)
 	}
 }
 
@@ -1002,9 +1066,7 @@ func (g *Game) carry_objects(screen *ebiten.Image, x, y float64, amount int, img
 		optst.GeoM.Translate(x+imgSize/2-3, y+float64(2.0*i)-10.0)
 
 		screen.DrawImage(
-			img.SubImage(
-				image.Rect(0, 0, int(tile.x), int(tile.y)),
-			).(*ebiten.Image),
+			subImage(img, image.Rect(0, 0, int(tile.x), int(tile.y))),
 			optst,
 		)
 		optst.GeoM.Reset()
@@ -1012,27 +1074,19 @@ func (g *Game) carry_objects(screen *ebiten.Image, x, y float64, amount int, img
 }
 
 // draw images caring on the head //
-func (g *Game) carry_plant(screen *ebiten.Image, x, y float64, amount int, img *ebiten.Image, varity string) {
+// every other crop is carried on the left side of the head
+func (g *Game) carry_plant(screen *ebiten.Image, x, y float64, amount int, img *ebiten.Image, crop *crops.Crop, index int) {
 	opt := &ebiten.DrawImageOptions{}
+	side := float64(imgSize / 2)
+	if index%2 == 1 {
+		side -= 16
+	}
 	for i := 5; i < 5+amount; i++ { // i=5 5 pix apart
-		if varity == wheat {
-			opt.GeoM.Translate(x+imgSize/2, y+float64(2.0*i)-10.0)
-			screen.DrawImage(
-				img.SubImage(
-					image.Rect(16*5, 0, 16*6, 16),
-				).(*ebiten.Image),
-				opt,
-			)
-		}
-		if varity == tomato {
-			opt.GeoM.Translate(x+imgSize/2-16, y+float64(2.0*i)-10.0)
-			screen.DrawImage(
-				img.SubImage(
-					image.Rect(16*5, 16, 16*6, 16*2),
-				).(*ebiten.Image),
-				opt,
-			)
-		}
+		opt.GeoM.Translate(x+side, y+float64(2.0*i)-10.0)
+		screen.DrawImage(
+			subImage(img, image.Rect(16*crop.Icon, 16*crop.Row, 16*crop.Icon+16, 16*crop.Row+16)),
+			opt,
+		)
 		opt.GeoM.Reset()
 	}
 }
@@ -1051,62 +1105,11 @@ func (g *Game) animTick() error {
 	return nil
 }
 
-func (g *Game) smoke_animation() {
-	if g.tick {
-		plant_anim = 32
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			plant_anim = 32 * 2
-		}
-	} else {
-		plant_anim = 32 * 3
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			plant_anim = 32 * 4
-		}
-	}
-}
-
-// set new sprite.frame 4 times every tick
-func (g *Game) fourTickAnim(spriteFrame int) int {
-	if g.tick {
-		spriteFrame = 16 * 0
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			spriteFrame = 16 * 1
-		}
-	} else {
-		spriteFrame = 16 * 2
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			spriteFrame = 16 * 3
-		}
-	}
-	return spriteFrame
-}
-
-// set new sprite.frame 4 times every tick
-// frame = sprite.frame
-// tileSize = image size 64*64 or 16*16 ...
-func (g *Game) animation(frame, tileSize int) int {
-	if g.tick {
-		frame = tileSize
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			frame = tileSize * 2
-		}
-	} else {
-		frame = tileSize * 3
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			frame = tileSize * 4
-		}
-	}
-	return frame
-}
-
 func (g *Game) drawItem(screen *ebiten.Image, pos Point, img *ebiten.Image, botPos Point) {
-	//g.animation(0, 64)
 	option := &ebiten.DrawImageOptions{}
 	option.GeoM.Translate(pos.x, pos.y) // position x, y on the screen
 	screen.DrawImage(
-		img.SubImage(
-			image.Rect(0, 0, int(botPos.x), int(botPos.y)), // top and bottom position of the image
-		).(*ebiten.Image),
+		subImage(img, image.Rect(0, 0, int(botPos.x), int(botPos.y))), // top and bottom position of the image
 		option,
 	)
 	option.GeoM.Reset()
@@ -1119,25 +1122,10 @@ func (g *Game) drawChestAnim(screen *ebiten.Image, pos Point, img *ebiten.Image,
 		topx = tileSize * 4 // pos 4 on Chest.png (tileSize space beteen chest)
 		botx = tileSize * 5
 	}
-	g.animation(0, 64)
 	option := &ebiten.DrawImageOptions{}
 	option.GeoM.Translate(pos.x, pos.y) // position x, y on the screen
 	screen.DrawImage(
-		img.SubImage(
-			image.Rect(int(topx), int(topy), int(botx), int(boty)), // top and bottom position of the image
-		).(*ebiten.Image),
-		option,
-	)
-	option.GeoM.Reset()
-}
-func (g *Game) drawChicken(screen *ebiten.Image, pos Point, frame int) {
-	g.animation(0, 64)
-	option := &ebiten.DrawImageOptions{}
-	option.GeoM.Translate(pos.x, pos.y) // position x, y
-	screen.DrawImage(
-		g.chickenImg.SubImage(
-			image.Rect(frame, 16, frame+16, 32), //row2, first interation: x=0,16 y=16,32
-		).(*ebiten.Image),
+		subImage(img, image.Rect(int(topx), int(topy), int(botx), int(boty))), // top and bottom position of the image
 		option,
 	)
 	option.GeoM.Reset()
@@ -1159,77 +1147,33 @@ func (g *Game) budda_animation() {
 		}
 	}
 }
-func (g *Game) drawSmoke(screen *ebiten.Image, x, y float64) {
-	if g.smokeSprite.active {
-		g.smoke_animation()
-		option := &ebiten.DrawImageOptions{}
-		option.GeoM.Translate(x, y) // position x, y
-		screen.DrawImage(
-			g.smokeSprite.img.SubImage(
-				image.Rect(plant_anim, 0, plant_anim+32, 32),
-			).(*ebiten.Image),
-			option,
-		)
-		option.GeoM.Reset()
-		g.smokeSprite.active = false
-	}
-}
 
-// TEST plants animation
-func (g *Game) plant_animation(frame int) {
-	plant_anim = 16 * frame
-}
+// draw plant at growth frame, the frame is the column in plants.png
 func (g *Game) drawPlants(screen *ebiten.Image, x, y float64, variety string, frame int) {
-	g.plant_animation(frame) // activate animation
+	crop, ok := g.crops.Get(variety)
+	if !ok {
+		return
+	}
+	row := 16 * crop.Row // one row in plants.png for every crop
 	option := &ebiten.DrawImageOptions{}
 	option.GeoM.Translate(x, y) // position x, y
-	if variety == "wheat" {
-		screen.DrawImage(
-			g.plantImg.SubImage(
-				//image.Rect(plant_anim, 0, plant_anim+imgSize, imgSize),
-				image.Rect(plant_anim, 0, plant_anim+16, 16),
-			).(*ebiten.Image),
-			option,
-		)
-		option.GeoM.Reset()
-	} else if variety == "tomato" {
-		screen.DrawImage(
-			g.plantImg.SubImage(
-				//image.Rect(plant_anim, 0, plant_anim+imgSize, imgSize),
-				image.Rect(plant_anim, 16, plant_anim+16, 16+16),
-			).(*ebiten.Image),
-			option,
-		)
-		option.GeoM.Reset()
-
-	}
+	screen.DrawImage(
+		subImage(g.plantImg, image.Rect(16*frame, row, 16*frame+16, row+16)),
+		option,
+	)
+	option.GeoM.Reset()
 }
 
 func (g *Game) drawWorker(screen *ebiten.Image, x, y float64, i int) {
 	option := &ebiten.DrawImageOptions{}
 	option.GeoM.Translate(x, y) // worker position x, y
 	screen.DrawImage(
-		g.workers[i].img.SubImage(
-			image.Rect(int(g.workers[i].rectTop.x), int(g.workers[i].rectTop.y), int(g.workers[i].rectBot.x), int(g.workers[i].rectBot.y)),
-		).(*ebiten.Image),
+		subImage(g.workers[i].img, g.workers[i].anim.Rect()),
 		option,
 	)
 	option.GeoM.Reset()
 }
 
-func (g *Game) coin_animation() {
-	if g.tick {
-		coin_anim = 0
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			coin_anim = 10
-		}
-	} else {
-		coin_anim = 20
-		if time.Since(g.lastUpdate) < gameSpeed/2 {
-			coin_anim = 30
-		}
-	}
-}
 func (g *Game) drawCoin(screen *ebiten.Image, x, y float64, coin Objects, index int) {
 	if coin.picked {
 		g.coins[index].pos = Point{-100, -100} // outside of screen
@@ -1238,9 +1182,7 @@ func (g *Game) drawCoin(screen *ebiten.Image, x, y float64, coin Objects, index
 	option := &ebiten.DrawImageOptions{}
 	option.GeoM.Translate(x, y) // coin position x, y
 	screen.DrawImage(
-		g.coins[index].img.SubImage(
-			image.Rect(coin_anim, 0, coin_anim+10, 10),
-		).(*ebiten.Image),
+		subImage(g.coins[index].img, g.coins[index].anim.Rect()),
 		option,
 	)
 	option.GeoM.Reset()
@@ -1248,92 +1190,86 @@ func (g *Game) drawCoin(screen *ebiten.Image, x, y float64, coin Objects, index
 
 // Arrowkeys to move or vim-keys "hjkl"
 func (g *Game) readKeys() {
-	if ebiten.IsKeyPressed(ebiten.KeyJ) || ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+	if keyPressed(actDown) || ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
 		g.dirDown()
 		g.Player.Dir.down = true
 	} else {
 		g.idle()
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyK) || ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+	if keyPressed(actUp) || ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
 		g.dirUp()
 		g.Player.Dir.up = true
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyH) || ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+	if keyPressed(actLeft) || ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
 		g.dirLeft()
 		g.Player.Dir.left = true
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyL) || ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+	if keyPressed(actRight) || ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
 		g.dirRight()
 		g.Player.Dir.right = true
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyF) { // Full screen
+	} else if keyJustPressed(actFullscreen) { // Full screen
 		g.fullScreen()
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyQ) { // Quit the game
+	} else if keyJustPressed(actQuit) { // Quit the game
 		g.quitGame()
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyEscape) { // Pause the game
+	} else if keyJustPressed(actPause) || gamepadJustPressed(ebiten.StandardGamepadButtonCenterRight) { // Pause the game
 		g.pauseGame()
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyA) { // Action key
+	} else if keyJustPressed(actAction) { // Action key
 		g.actionKey()
-	} else if inpututil.IsKeyJustPressed(ebiten.Key0) { // scene 0
-		g.scene = 0
-	} else if inpututil.IsKeyJustPressed(ebiten.Key1) { //  scene 1
-		g.scene = 1
-	} else if inpututil.IsKeyJustPressed(ebiten.Key2) { //  scene 2
-		g.scene = 2
-	} else if inpututil.IsKeyJustPressed(ebiten.Key3) { //  scene 3
-		g.scene = 3
+	} else if keyJustPressed(actWorkers) { // Worker panel
+		g.workerPanelKey()
+	} else if keyJustPressed(actTill) { // Till grass
+		g.tillKey()
+	} else if keyJustPressed(actPlant) { // Plant seed
+		g.plantKey()
+	} else if keyJustPressed(actWater) { // Water farmland
+		g.waterKey()
+	} else if keyJustPressed(actBag) { // Inventory bag
+		g.inventoryKey()
+	} else if keyJustPressed(actInteract) { // Open chest, place chest or place construction site
+		g.interactKey()
+	} else if keyJustPressed(actBuild) { // Build menu
+		g.buildKey()
+	} else if keyJustPressed(actNext) { // Next blueprint
+		g.nextBlueprintKey()
+	} else if keyJustPressed(actCalendar) { // Calendar
+		g.calendarKey()
+	} else if keyJustPressed(actQuests) { // Quest log
+		g.questLogKey()
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyF3) { // Draw stats
+		g.debugOpen = !g.debugOpen
+	} else if keyJustPressed(actSave) { // Save game
+		g.saveGame()
+	} else if keyJustPressed(actLoad) { // Load game
+		g.loadGame()
+	} else if inpututil.IsKeyJustPressed(ebiten.Key0) && g.inside == nil { // scene 0
+		g.setScene(0)
+	} else if inpututil.IsKeyJustPressed(ebiten.Key1) && g.inside == nil { //  scene 1
+		g.setScene(1)
+	} else if inpututil.IsKeyJustPressed(ebiten.Key2) && g.inside == nil { //  scene 2
+		g.setScene(2)
+	} else if inpututil.IsKeyJustPressed(ebiten.Key3) && g.inside == nil { //  scene 3
+		g.setScene(3)
 	}
 }
 
 // F key for full screen
 func (g *Game) fullScreen() {
-	if !g.fullWindow {
-		ebiten.SetFullscreen(true)
-		g.fullWindow = true
-	} else {
-		g.fullWindow = false
-		ebiten.SetFullscreen(false)
-	}
+	options.Fullscreen = !options.Fullscreen
+	applyOptions()
+	saveOptions()
 }
 
 // Q key for quit
 func (g *Game) quitGame() {
+	saveProfile()
 	g.exitGame = true
 }
 
-// Escape-key to Pause the game
-func (g *Game) pauseGame() {
-	if !g.gamePause {
-		g.gamePause = true
-	} else {
-		g.gamePause = false
-	}
-}
-func (g *Game) pause(screen *ebiten.Image) {
-	// Pause the game
-	vector.DrawFilledRect(
-		screen,
-		float32(20),     // x position
-		float32(20),     // y position
-		screenWidth-40,  // width size
-		screenHeight-40, // Height size
-		blue_transp,
-		true,
-	)
-	addText(screen, 32, "Pause", black, screenWidth+5, screenHeight/3+4)
-	addText(screen, 32, "Pause", yellow, screenWidth, screenHeight/3)
-	addText(screen, 16, "Pause the Game - Esc", yellow, screenWidth, screenHeight/3+100)
-	addText(screen, 16, "Quit the game - q", yellow, screenWidth, screenHeight/3+150)
-	addText(screen, 16, "Full screen - f", yellow, screenWidth, screenHeight/3+200)
-	addText(screen, 16, "Action key - a", yellow, screenWidth, screenHeight/3+250)
-	addText(screen, 16, "Change scene key: 0-3", purple, screenWidth, screenHeight/3+300)
-	addText(screen, 20, "*********************", green, screenWidth, screenHeight/3+500)
-}
-
 func (g Game) menuText(screen *ebiten.Image) {
 	if g.infoBoxSpite.active {
-		addText(screen, 10, "Pause the Game - Esc", black, 780, 630)
-		addText(screen, 10, "Quit the game - q", blue, 780, 650)
-		addText(screen, 10, "Full screen - f", purple, 780, 670)
+		addText(screen, 10, "Pause the Game - "+keyOf(actPause).String(), black, 780, 630)
+		addText(screen, 10, "Quit the game - "+keyOf(actQuit).String(), blue, 780, 650)
+		addText(screen, 10, "Full screen - "+keyOf(actFullscreen).String(), purple, 780, 670)
 		addText(screen, 10, "Move - arrowkey", red, 780, 690)
 	}
 }
@@ -1353,46 +1289,13 @@ func (g *Game) drawinfoBox(screen, img *ebiten.Image, x, y float64) {
 		option := &ebiten.DrawImageOptions{}
 		option.GeoM.Translate(x, y) // position x, y
 		screen.DrawImage(
-			img.SubImage(
-				image.Rect(0, 0, 400, 64),
-			).(*ebiten.Image),
+			subImage(img, image.Rect(0, 0, 400, 64)),
 			option,
 		)
 		option.GeoM.Reset()
 	}
 }
 
-// TEST add button
-func (g *Game) menu() {
-
-	s, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	fontFace := &text.GoTextFace{
-		Source: s,
-		Size:   32,
-	}
-	button := widget.NewButton(
-		// specify the images to use
-		//widget.ButtonOpts.Image(g.addBottonImg),
-
-		// specify the button's text, the font face, and the color
-		widget.ButtonOpts.Text("Hello, World!", fontFace, &widget.ButtonTextColor{
-			Idle: color.RGBA{0xdf, 0xf4, 0xff, 0xff},
-		}),
-
-		// specify that the button's text needs some padding for correct display
-		widget.ButtonOpts.TextPadding(widget.Insets{
-			Left:  30,
-			Right: 30,
-		}),
-		// ... click handler, etc. ...
-	)
-	button.Update()
-}
-
 func addText(screen *ebiten.Image, textSize int, t string, color color.Color, width, height float64) {
 	face := &text.GoTextFace{
 		Source: mplusFaceSource,
@@ -1417,6 +1320,18 @@ func addText(screen *ebiten.Image, textSize int, t string, color color.Color, wi
 	)
 }
 
+// add text with top left corner at x,y
+func addTextAt(screen *ebiten.Image, textSize int, t string, color color.Color, x, y float64) {
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   float64(textSize),
+	}
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(color)
+	text.Draw(screen, t, face, op)
+}
+
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return screenWidth, screenHeight
 }
@@ -1429,15 +1344,65 @@ func checkErr(err error) {
 }
 
 func main() {
-	// Window properties
-	ebiten.SetWindowSize(screenWidth*2, screenHeight*2)
+	// Window properties, the window size is in the options
 	ebiten.SetWindowTitle("Gopher Land")
+	ebiten.SetWindowClosingHandled(true) // Update save the profile before the window close
 
 	// Text, font
 	textsource, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
 	checkErr(err)
 	mplusFaceSource = textsource
 
+	loadOptions()
+	loadProfile()
+	g := newGame()
+	g.newMenu()
+
+	////// play background music //////
+	_ = audio.NewContext(SampleRate)
+	stream, err := vorbis.DecodeWithSampleRate(SampleRate, bytes.NewReader(audioBG))
+	checkErr(err)
+
+	// infinite loop Bg music
+	audioPlayer, err := audio.CurrentContext().NewPlayer(
+		audio.NewInfiniteLoop(stream,
+			int64(len(audioBG)*6*SampleRate)))
+	checkErr(err)
+
+	//audioPlayer, _ := audio.CurrentContext().NewPlayer(stream)
+	// you pass the audio player to your game struct, and just call
+	musicPlayer = audioPlayer
+	applyOptions()
+	audioPlayer.Play() //when you want your music to start, and
+	// audioPlayer.Pause()
+
+	//	// chose audio file to on scene 0-1
+	//	if g.scene == 1 {
+	//		playSound(audioVillage)
+	//	}
+	//	if g.scene == 0 {
+	//		playSound(audioBG)
+	//	}
+
+	// Start game
+	if err := ebiten.RunGame(g); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// new game with all images, data and the village at the start
+func newGame() *Game {
+	// farm tilemap for scene 0, only the farmland layer
+	farmJSON, err := tilemaps.NewTilemapJSON("assets/map/farm_bg.json")
+	checkErr(err)
+	if farmJSON.Layer(farmLayer) == nil {
+		farmJSON.AddLayer(farmLayer, screenWidth/tileSize, screenHeight/tileSize+1)
+	}
+
+	// images and data files are loaded once, at the first new game
+	a := gameAssets()
+	atlas := a.atlas
+
 	// TilemapJSON1
 	tilemapJSON1, err := tilemaps.NewTilemapJSON("assets/map/level1_bg.json")
 	checkErr(err)
@@ -1450,73 +1415,56 @@ func main() {
 	tilemapJSON3, err := tilemaps.NewTilemapJSON("assets/map/water_bg.json")
 	checkErr(err)
 
-	// load tilemapImg image to tilemap 1 - 2
-	tilemapImg, _, err := ebitenutil.NewImageFromFile("assets/map/tileset_floor.png")
-	checkErr(err)
-
-	// load tilemapImg image to tilemap water
-	tilemapImgWater, _, err := ebitenutil.NewImageFromFile("assets/map/TilesetWater.png")
-	checkErr(err)
-
 	// load village image
-	old_village, _, err := ebitenutil.NewImageFromFile("assets/images/village_old.png")
-	checkErr(err)
+	oldVillageSheet := a.oldVillage
+	old_village := atlas.Image(oldVillageSheet.Image)
 
 	// load village house image
-	new_village, _, err := ebitenutil.NewImageFromFile("assets/images/TilesetHouse.png")
-	checkErr(err)
-
-	// load background image
-	bgImg, _, err := ebitenutil.NewImageFromFile("assets/images/grass.png")
-	checkErr(err)
+	newVillageSheet := a.newVillage
+	new_village := atlas.Image(newVillageSheet.Image)
 
 	// load Player image
-	playerImg, _, err := ebitenutil.NewImageFromFile("assets/images/playerBlue.png")
-	checkErr(err)
+	playerImg := atlas.Image("assets/images/playerBlue.png")
 
 	// load Worker image
-	workerImg, _, err := ebitenutil.NewImageFromFile("assets/images/player.png")
-	checkErr(err)
+	workerImg := atlas.Image("assets/images/player.png")
 
 	// load Work image
-	workImg, _, err := ebitenutil.NewImageFromFile("assets/images/workers.png")
-	checkErr(err)
+	workImg := atlas.Image("assets/images/workers.png")
 
 	// load coin image
-	coinImg, _, err := ebitenutil.NewImageFromFile("assets/images/coin2.png")
-	checkErr(err)
+	coinImg := atlas.Image(a.coin.Image)
 
 	// load chicken image
-	chickenImg, _, err := ebitenutil.NewImageFromFile("assets/images/chicken.png")
-	checkErr(err)
+	chickenImg := atlas.Image(a.chicken.Image)
 
 	// load chicken image
-	eggImg, _, err := ebitenutil.NewImageFromFile("assets/images/Egg.png")
-	checkErr(err)
+	eggImg := atlas.Image("assets/images/Egg.png")
 
 	// load chicken_house image
-	chicken_houseImg, _, err := ebitenutil.NewImageFromFile("assets/images/Chicken_House.png")
-	checkErr(err)
+	chicken_houseImg := atlas.Image("assets/images/Chicken_House.png")
 
 	// load info box background image
-	infoBoxImg, _, err := ebitenutil.NewImageFromFile("assets/images/InfoBox.png")
-	checkErr(err)
+	infoBoxImg := atlas.Image("assets/images/InfoBox.png")
 
 	// load plants image
-	plantImg, _, err := ebitenutil.NewImageFromFile("assets/images/plants.png")
-	checkErr(err)
+	plantImg := atlas.Image("assets/images/plants.png")
 
 	// load plants image
-	chestImg, _, err := ebitenutil.NewImageFromFile("assets/images/Chest.png")
-	checkErr(err)
+	chestImg := atlas.Image("assets/images/Chest.png")
+
+	// load fire image
+	fireImg := atlas.Image("assets/images/Fire.png")
+
+	// load biom image, wood and stone icons
+	biomImg := atlas.Image("assets/images/biom.png")
 
 	// 	// load add-button image
 	// 	addButton, _, err := ebitenutil.NewImageFromFile("assets/images/add-button64.png")
 	// 	checkErr(err)
 
 	// load smoke image
-	smokeImg, _, err := ebitenutil.NewImageFromFile("assets/images/smoke.png")
-	checkErr(err)
+	smokeImg := atlas.Image("assets/images/smoke.png")
 
 	// Game constructor. add Player
 	g := &Game{
@@ -1527,13 +1475,18 @@ func main() {
 				//pos: Point{screenWidth/2 - (imgSize / 2), screenHeight/2 - (imgSize / 2)},
 			},
 			speed:      PlayerSpeed,
-			coin:       0,
 			wallet:     2,
 			basketSize: 2,
+			inv:        NewInventory(playerSlots, stackSize),
 		},
 	}
-	g.Player.rectTop = Point{g.Player.pos.y + imgSize/4, g.Player.pos.y + imgSize/4}
-	g.Player.rectBot = Point{imgSize / 2, imgSize / 2}
+	// start with some seeds in the hotbar
+	g.Player.inv.Add(seedItem(wheat), 3)
+	g.Player.inv.Add(seedItem(tomato), 3)
+	g.Player.inv.Add(seedItem("carrot"), 2)
+	g.Player.inv.Add(seedItem("corn"), 1)
+	g.Player.inv.Add(seedItem("pumpkin"), 1)
+	g.Player.anim = NewAnimator(characterAnim, "idle")
 
 	// add 10 workers
 	for i := 0; i < 10; i++ {
@@ -1543,13 +1496,13 @@ func main() {
 				pos:     Point{40, 20*float64(i) + 60},
 				rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
 			},
-			speed: 1.5,
-			dest:  Point{screenWidth - imgSize - (float64(i * imgSize)), screenHeight/2 - imgSize - (float64(i * imgSize))},
+			speed:  1.5,
+			morale: moraleStart,
+			dest:   Point{screenWidth - imgSize - (float64(i * imgSize)), screenHeight/2 - imgSize - (float64(i * imgSize))},
 		})
 	}
-	for i := range g.workers { //set rectTop and rectBot for animation
-		g.workers[i].rectTop = Point{0, 0}
-		g.workers[i].rectBot = Point{imgSize, imgSize}
+	for i := range g.workers { // every worker has its own animation
+		g.workers[i].anim = newRandomAnimator(characterAnim, "idle")
 		g.workers[i].dest = Point{200 + (float64(i) * 30), 90}
 		g.workers[i].active = false // start with inactive workers. buddaSpawn activate workers
 	}
@@ -1561,6 +1514,7 @@ func main() {
 				img:     coinImg,
 				pos:     Point{screenWidth/2 + 30 + float64(i)*10, screenHeight/2 + houseTileSize - 30.0},
 				rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
+				anim:    newRandomAnimator(coinAnim, "spin"),
 			},
 			variety: "coin",
 		})
@@ -1574,7 +1528,7 @@ func main() {
 				rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
 				active:  false,
 			},
-			variety: "wheat",
+			variety: wheat,
 		})
 
 		g.plants = append(g.plants, &Objects{
@@ -1584,7 +1538,7 @@ func main() {
 				rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
 				active:  false,
 			},
-			variety: "tomato",
+			variety: tomato,
 		})
 	}
 	// add 10 chickens
@@ -1594,9 +1548,12 @@ func main() {
 				img:     chickenImg,
 				pos:     randomPoint(), // start at random point
 				rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
+				anim:    newRandomAnimator(chickenAnim, "walk"),
+				active:  true,
 			},
 			variety:  "chicken",
 			pickable: true,
+			age:      growUpTime, // adult chickens
 		})
 	}
 	// add 10 eggs
@@ -1617,7 +1574,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     old_village,
 			pos:     Point{250, houseTileSize},
-			rectPos: image.Rect(0, 0, houseTileSize, imgSize),
+			rectPos: sheetRect(oldVillageSheet, "house_roof"),
 			active:  true,
 		},
 		variety: "house",
@@ -1626,7 +1583,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     old_village,
 			pos:     Point{100, 100},
-			rectPos: image.Rect(houseTileSize, 0, houseTileSize*2, imgSize),
+			rectPos: sheetRect(oldVillageSheet, "house"),
 			active:  true,
 		},
 		variety: "house",
@@ -1635,7 +1592,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     old_village,
 			pos:     Point{400, imgSize},
-			rectPos: image.Rect(houseTileSize*2+imgSize, 0, houseTileSize*2+imgSize*2, imgSize),
+			rectPos: sheetRect(oldVillageSheet, "small_house"),
 			active:  true,
 		},
 		variety: "small_house",
@@ -1644,7 +1601,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     old_village,
 			pos:     Point{500, imgSize},
-			rectPos: image.Rect(houseTileSize*2+imgSize, imgSize, houseTileSize*2+imgSize*2, imgSize*2),
+			rectPos: sheetRect(oldVillageSheet, "small_house_2"),
 			active:  true,
 		},
 		variety: "small_house",
@@ -1654,7 +1611,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{250, houseTileSize},
-			rectPos: image.Rect(0, 0, houseTileSize, imgSize),
+			rectPos: sheetRect(newVillageSheet, "new_house"),
 			active:  false,
 		},
 		variety: "new_house",
@@ -1664,7 +1621,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     old_village,
 			pos:     Point{screenWidth/2 + houseTileSize, screenHeight/2 + houseTileSize},
-			rectPos: image.Rect(0, imgSize, imgSize-16, imgSize*2-16),
+			rectPos: sheetRect(oldVillageSheet, "budda"),
 			active:  true,
 		},
 		variety: "budda",
@@ -1673,7 +1630,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{screenWidth/2 + houseTileSize, screenHeight/2 + houseTileSize},
-			rectPos: image.Rect(imgSize*2-15, imgSize*6+16, imgSize*3-32, imgSize*7),
+			rectPos: sheetRect(newVillageSheet, "budda_gray"),
 			active:  false,
 		},
 		variety: "budda",
@@ -1682,7 +1639,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{screenWidth/2 + houseTileSize, screenHeight/2 + houseTileSize},
-			rectPos: image.Rect(imgSize*1, imgSize*6+16, imgSize*2-16, imgSize*7),
+			rectPos: sheetRect(newVillageSheet, "budda_gray_pearl"),
 			active:  false,
 		},
 		variety: "budda",
@@ -1691,7 +1648,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{screenWidth/2 + houseTileSize, screenHeight/2 + houseTileSize},
-			rectPos: image.Rect(imgSize*2-16, imgSize*5, imgSize*2+16, imgSize*6-16),
+			rectPos: sheetRect(newVillageSheet, "budda_orange"),
 			active:  false,
 		},
 		variety: "budda",
@@ -1700,7 +1657,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{screenWidth/2 + houseTileSize, screenHeight/2 + houseTileSize},
-			rectPos: image.Rect(imgSize, imgSize*5, imgSize*2-16, imgSize*6-16),
+			rectPos: sheetRect(newVillageSheet, "budda_orange_pearl"),
 			active:  false,
 		},
 		variety: "budda",
@@ -1710,7 +1667,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{100, 100},
-			rectPos: image.Rect(houseTileSize, 0, houseTileSize*2, imgSize),
+			rectPos: sheetRect(newVillageSheet, "new_house_2"),
 			active:  false,
 		},
 		variety: "new_house",
@@ -1720,7 +1677,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{400, imgSize},
-			rectPos: image.Rect(houseTileSize*5-16, imgSize*6+16, houseTileSize*5+imgSize-16, imgSize*6+imgSize+16),
+			rectPos: sheetRect(newVillageSheet, "new_house_small"),
 			active:  false,
 		},
 		variety: "new_house_small",
@@ -1730,7 +1687,7 @@ func main() {
 		Sprite: &Sprite{
 			img:     new_village,
 			pos:     Point{500, imgSize},
-			rectPos: image.Rect(houseTileSize*5-16, imgSize*6+16, houseTileSize*5+imgSize-16, imgSize*6+imgSize+16),
+			rectPos: sheetRect(newVillageSheet, "new_house_small"),
 			active:  false,
 		},
 		variety: "new_house_small",
@@ -1760,16 +1717,19 @@ func main() {
 	})
 
 	// Add Images and tilemapJSON
-	g.bgImg = bgImg
-	g.village = old_village
-	g.tilemapImg = tilemapImg
-	g.tilemapImgWater = tilemapImgWater
+	g.tilemapImg = a.tilemapImg
+	g.bgSeasons = a.bgSeasons
+	g.tilesetSeasons = a.tilesetSeasons
+	g.waterSeasons = a.waterSeasons
 	g.plantImg = plantImg
 	g.workImg = workImg
 	g.workerIdleImg = workerImg
 	g.coinImg = coinImg
 	g.chickenImg = chickenImg
 	g.eggImg = eggImg
+	g.chestImg = chestImg
+	g.biomImg = biomImg
+	g.buildingImgs = a.buildingImgs
 
 	// info box background
 	g.infoBoxSpite = &Sprite{
@@ -1778,48 +1738,51 @@ func main() {
 		active: true,
 	}
 
-	// smoke sprite
-	g.smokeSprite = &Sprite{
-		img:    smokeImg,
-		pos:    Point{50, 50},
-		active: false,
-	}
-
 	g.tilemapJSON1 = tilemapJSON1
 	g.tilemapJSON2 = tilemapJSON2
 	g.tilemapJSON3 = tilemapJSON3
+	g.crops = a.crops
+	g.tiers = a.tiers
+	g.buildings = a.buildings
+	g.market = g.newMarket()
+	g.farmJSON = farmJSON
+	g.tilled = make(map[int]int)
+	g.clock = Clock{ticks: startHour * 60 * minuteTicks}
+	g.lightImg = a.lightImg
+	g.weather = NewWeather(time.Now().UnixNano(), 0)
+	g.fires = append(g.fires, &Campfire{Sprite: &Sprite{img: fireImg, pos: Point{300, 160}, active: true, anim: NewAnimator(fireAnim, "burn")}}) // village campfire
+	g.initParticles(smokeImg)
+	checkErr(g.loadInteriors(a.interiors, oldVillageSheet, old_village, workerImg))
+	g.hud = g.newHUD()
+	g.dialogues = a.dialogues
+	g.flags = make(map[string]bool)
+	g.portraits = make(map[string]*ebiten.Image)
+	for _, d := range a.dialogues.Dialogues {
+		g.portraits[d.Portrait.Image] = atlas.Image(d.Portrait.Image)
+		for _, n := range d.Nodes {
+			if n.Portrait != nil {
+				g.portraits[n.Portrait.Image] = atlas.Image(n.Portrait.Image)
+			}
+		}
+	}
+	for i := range g.workers { // first plants are the workers fields
+		g.plants[i].worker = g.workers[i]
+	}
 
-	g.scene = 0 // scene or level, 4 different backgrounds
-
-	////// play background music //////
-	_ = audio.NewContext(SampleRate)
-	stream, err := vorbis.DecodeWithSampleRate(SampleRate, bytes.NewReader(audioBG))
-	checkErr(err)
-
-	// infinite loop Bg music
-	audioPlayer, err := audio.CurrentContext().NewPlayer(
-		audio.NewInfiniteLoop(stream,
-			int64(len(audioBG)*6*SampleRate)))
-	checkErr(err)
-
-	//audioPlayer, _ := audio.CurrentContext().NewPlayer(stream)
-	// you pass the audio player to your game struct, and just call
-	audioPlayer.SetVolume(0.2)
-	audioPlayer.Play() //when you want your music to start, and
-	// audioPlayer.Pause()
-
-	//	// chose audio file to on scene 0-1
-	//	if g.scene == 1 {
-	//		playSound(audioVillage)
-	//	}
-	//	if g.scene == 0 {
-	//		playSound(audioBG)
-	//	}
+	g.questsData = a.quests
+	g.startQuests()
+	g.events = NewEventBus()
+	subscribeAudio(g.events)
+	subscribeParticles(g.events)
+	subscribeStats(g.events)
+	subscribeQuests(g.events)
+	subscribeProfile(g.events)
+	subscribeToasts(g.events)
+	subscribeMarket(g.events)
+	g.achievements = a.achievements
 
-	// Start game
-	if err := ebiten.RunGame(g); err != nil {
-		log.Fatal(err)
-	}
+	g.scene = 0 // scene or level, 4 different backgrounds
+	return g
 }
 
 func playSound(sound []byte) {
@@ -1827,7 +1790,6 @@ func playSound(sound []byte) {
 	stream, err := vorbis.DecodeWithSampleRate(SampleRate, bytes.NewReader(sound))
 	checkErr(err)
 	audioPlayer, _ := audio.CurrentContext().NewPlayer(stream)
-	// TEST add parameter to the func volume float 0.0 - 1.0
-	audioPlayer.SetVolume(0.3)
+	audioPlayer.SetVolume(soundVolume * options.Sound)
 	audioPlayer.Play()
 }
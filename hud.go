@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ebitenui/ebitenui"
+	eimage "github.com/ebitenui/ebitenui/image"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	chickenGoal = 10 // chickens to deliver to the chicken house
+	hudTextSize = 10
+	hudBarW     = 100
+	hudBarH     = 6
+)
+
+// HUD is the heads up display. It's laid out by ebitenui and anchored to
+// the corners of the screen, so it follow the screen when the window is scaled
+type HUD struct {
+	ui       *ebitenui.UI
+	coins    *widget.Text
+	basket   *widget.Text
+	eggs     *widget.Text
+	chickens *widget.Text
+	tier     *widget.Text
+	progress *widget.ProgressBar
+	clock    *widget.Text
+	hint     *widget.Text // next quest objective
+}
+
+// HUD with coins, basket, eggs, chickens and the quest hint in the top left
+// corner and village progress and clock in the top right corner
+func (g *Game) newHUD() *HUD {
+	h := &HUD{}
+	face := &text.GoTextFace{Source: mplusFaceSource, Size: hudTextSize}
+	label := func() *widget.Text {
+		return widget.NewText(widget.TextOpts.Text("", face, white))
+	}
+	h.coins, h.basket, h.eggs, h.chickens = label(), label(), label(), label()
+	h.tier, h.clock = label(), label()
+	h.hint = widget.NewText(widget.TextOpts.Text("", face, yellow))
+	h.progress = widget.NewProgressBar(
+		widget.ProgressBarOpts.WidgetOpts(widget.WidgetOpts.MinSize(hudBarW, hudBarH)),
+		widget.ProgressBarOpts.Images(
+			&widget.ProgressBarImage{Idle: eimage.NewNineSliceColor(blue_transp)},
+			&widget.ProgressBarImage{Idle: eimage.NewNineSliceColor(yellow)},
+		),
+		widget.ProgressBarOpts.Values(0, 100, 0),
+	)
+
+	left := hudPanel(widget.AnchorLayoutPositionStart)
+	left.AddChild(
+		g.hudRow(coinItem, h.coins),
+		g.hudRow(g.crops.Crops[0].Name, h.basket), // the basket hold crops
+		g.hudRow(eggItem, h.eggs),
+		g.hudRow(chickenItem, h.chickens),
+		h.hint,
+	)
+	right := hudPanel(widget.AnchorLayoutPositionEnd)
+	right.AddChild(h.tier, h.progress, h.clock)
+
+	root := widget.NewContainer(widget.ContainerOpts.Layout(widget.NewAnchorLayout(
+		widget.AnchorLayoutOpts.Padding(widget.NewInsetsSimple(4)),
+	)))
+	root.AddChild(left, right)
+	h.ui = &ebitenui.UI{Container: root, DisableDefaultFocus: true} // Tab is the worker panel
+	return h
+}
+
+// panel in the top left or top right corner, rows top down
+func hudPanel(side widget.AnchorLayoutPosition) *widget.Container {
+	return widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(eimage.NewNineSliceColor(blue_transp)),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Padding(widget.NewInsetsSimple(4)),
+			widget.RowLayoutOpts.Spacing(2),
+		)),
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+			HorizontalPosition: side,
+			VerticalPosition:   widget.AnchorLayoutPositionStart,
+		})),
+	)
+}
+
+// item icon and a label
+func (g *Game) hudRow(item string, label *widget.Text) *widget.Container {
+	row := widget.NewContainer(widget.ContainerOpts.Layout(widget.NewRowLayout(
+		widget.RowLayoutOpts.Spacing(4),
+	)))
+	img, rect := g.itemIcon(item)
+	icon := img.SubImage(rect.Add(img.Bounds().Min)).(*ebiten.Image) // atlas image, see subImage
+	label.GetWidget().LayoutData = widget.RowLayoutData{Position: widget.RowLayoutPositionCenter}
+	row.AddChild(widget.NewGraphic(widget.GraphicOpts.Image(icon)), label)
+	return row
+}
+
+// update the labels from the game state
+func (g *Game) updateHUD() {
+	h := g.hud
+	h.coins.Label = fmt.Sprintf("%d/%d", g.Player.inv.Count(coinItem), g.Player.wallet)
+	h.basket.Label = fmt.Sprintf("%d/%d %s", g.basketCount(), g.Player.basketSize, g.basketText())
+	h.eggs.Label = fmt.Sprint(g.Player.inv.Count(eggItem))
+	h.chickens.Label = fmt.Sprintf("%d/%d", min(g.chickensDelivered, chickenGoal), chickenGoal)
+	h.tier.Label = g.tiers.Tiers[g.tier].Name
+	h.progress.SetCurrent(int(g.tierProgress() * 100))
+	h.clock.Label = g.clock.String() + "  " + g.weather.state
+	h.hint.Label = g.questHint()
+	h.ui.Update()
+}
+
+// crops in the basket, like "wheat 2 corn 1"
+func (g *Game) basketText() string {
+	var parts []string
+	for _, crop := range g.crops.Crops {
+		if n := g.Player.inv.Count(crop.Name); n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", crop.Name, n))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// draw the HUD over the world
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	g.hud.ui.Draw(screen)
+}
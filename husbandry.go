@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	grainItem    = wheat    // chickens eat grain from the wheat harvest
+	hungerMax    = 100      // starving chicken
+	hungerTicks  = 60 * 3   // ticks between every hunger rise
+	hungry       = 50       // hungry chicken eat from the feeder and don't lay eggs
+	grainFood    = 50       // hunger one grain take away
+	feederSize   = 20       // grain in the coop feeder
+	layTime      = 60 * 60  // ticks a fed hen in the coop take to lay an egg
+	hatchTime    = 60 * 45  // ticks before an egg in the coop hatch
+	growUpTime   = 60 * 120 // ticks before a chick is an adult
+	coopCapacity = 6        // chickens in one chicken house
+	chickScale   = 0.6      // chicks are drawn smaller
+)
+
+// chicken is running free, the Player can pick it up
+func (c *Objects) roaming() bool {
+	return c.active && c.pickable
+}
+
+// chicken live in the coop
+func (c *Objects) housed() bool {
+	return c.active && !c.pickable && c.picked
+}
+
+// chicken is a chick until it has grown up
+func (c *Objects) chick() bool {
+	return c.age < growUpTime
+}
+
+// chicken house the Player deliver chickens and grain to
+func (g *Game) chickenHouse() *Objects {
+	for _, house := range g.house {
+		if house.active && house.variety == "chicken_house" {
+			return house
+		}
+	}
+	return nil
+}
+
+// chickens that can live in the coop, from chicken houses and built coops
+func (g *Game) coopSize() int {
+	n := 0
+	for _, house := range g.house {
+		if house.active && house.variety == "chicken_house" {
+			n += coopCapacity
+		}
+	}
+	for _, b := range g.buildingSites {
+		if b.done() {
+			n += b.blueprint.Effect.Coop
+		}
+	}
+	return n
+}
+
+// chickens living in the coop
+func (g *Game) housedChickens() int {
+	n := 0
+	for _, c := range g.chickens {
+		if c.housed() {
+			n++
+		}
+	}
+	return n
+}
+
+// random point around the chicken house
+func coopPoint() Point {
+	pos := randomPoint()
+	if pos.x <= 500 {
+		pos.x = 500
+	} else if pos.x >= screenWidth-20 {
+		pos.x = screenWidth - 20
+	}
+	if pos.y <= screenHeight/2-50 {
+		pos.y = screenHeight/2 - 50
+	} else if pos.y >= screenHeight-100 {
+		pos.y = screenHeight - 100
+	}
+	return pos
+}
+
+// chickens get hungry and eat from the feeder. Fed hens in the coop lay eggs,
+// eggs in the coop hatch and chicks grow up
+func (g *Game) updateChickens() {
+	for _, c := range g.chickens {
+		if !c.active {
+			continue // carried by the Player
+		}
+		c.age++
+		if c.age%hungerTicks == 0 {
+			c.hunger = min(hungerMax, c.hunger+1)
+		}
+		if !c.housed() {
+			continue
+		}
+		if c.hunger >= hungry && g.coopFeed > 0 {
+			g.coopFeed--
+			c.hunger = max(0, c.hunger-grainFood)
+		}
+		if c.chick() || c.hunger >= hungry {
+			continue
+		}
+		c.layTicks++
+		if c.layTicks >= layTime {
+			c.layTicks = 0
+			g.layEgg(c.pos)
+		}
+	}
+	for _, egg := range g.eggs {
+		if !egg.active || !egg.pickable {
+			continue
+		}
+		egg.age++
+		if egg.age >= hatchTime && g.housedChickens() < g.coopSize() {
+			g.hatch(egg)
+		}
+	}
+}
+
+// new egg on the ground, an inactive egg is used again
+func (g *Game) layEgg(pos Point) *Objects {
+	for _, egg := range g.eggs {
+		if !egg.active {
+			egg.active = true
+			egg.pickable = true
+			egg.picked = false
+			egg.pos = pos
+			egg.age = 0
+			return egg
+		}
+	}
+	e := &Objects{
+		Sprite: &Sprite{
+			active:  true,
+			img:     g.eggImg,
+			pos:     pos,
+			rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
+		},
+		variety:  egg,
+		pickable: true,
+	}
+	g.eggs = append(g.eggs, e)
+	return e
+}
+
+// egg hatch into a chick living in the coop
+func (g *Game) hatch(egg *Objects) {
+	egg.active = false
+	egg.pickable = false
+	g.chickens = append(g.chickens, &Objects{
+		Sprite: &Sprite{
+			img:     g.chickenImg,
+			pos:     egg.pos,
+			rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
+			anim:    newRandomAnimator(chickenAnim, "walk"),
+			active:  true,
+		},
+		variety: chicken,
+		picked:  true,
+		dest:    coopPoint(),
+	})
+	playSound(audioSecret)
+}
+
+// Player pick up a roaming chicken, or feed it if grain is selected in the hotbar
+func (g *Game) pickChicken(c *Objects) {
+	if g.activeItem().Item == grainItem && c.hunger >= hungry {
+		g.Player.inv.Remove(grainItem, 1)
+		c.hunger = max(0, c.hunger-grainFood)
+		playSound(audioFx)
+		return
+	}
+	if g.Player.inv.Count(chickenItem) < 1 { // pick one at a time
+		g.Player.inv.Add(chickenItem, 1)
+		c.active = false
+		c.pickable = false
+		c.picked = true
+		Publish(g, ItemPicked{chickenItem, 1, Point{c.pos.x + 8, c.pos.y + 8}})
+	} else {
+		Publish(g, ActionFailed{"Carrying a chicken", chickenItem})
+	}
+}
+
+// Player visit the chicken house. Deliver the carried chicken and fill the feeder with grain
+func (g *Game) chickenHouseVisit() {
+	if g.Player.inv.Count(chickenItem) > 0 && g.housedChickens() >= g.coopSize() {
+		Publish(g, ActionFailed{"Chicken house full", chickenItem})
+	} else if g.Player.inv.Count(chickenItem) > 0 {
+		for _, c := range g.chickens {
+			if !c.active && c.picked {
+				c.active = true
+				c.pos = coopPoint()
+				c.dest = coopPoint()
+				break
+			}
+		}
+		g.Player.inv.Remove(chickenItem, 1)
+		Publish(g, ChickenDelivered{})
+	}
+	if n := min(feederSize-g.coopFeed, g.Player.inv.Count(grainItem)); n > 0 {
+		g.coopFeed += g.Player.inv.Remove(grainItem, n)
+		playSound(audioCoin)
+	}
+}
+
+// draw chicken, chicks are smaller. Hungry chickens have a red mark
+func (g *Game) drawChicken(screen *ebiten.Image, c *Objects) {
+	option := &ebiten.DrawImageOptions{}
+	if c.chick() {
+		option.GeoM.Scale(chickScale, chickScale)
+		option.GeoM.Translate(16*(1-chickScale)/2, 16*(1-chickScale))
+	}
+	option.GeoM.Translate(c.pos.x, c.pos.y) // position x, y
+	screen.DrawImage(
+		subImage(g.chickenImg, c.anim.Rect()), // walk frames in row 2
+		option,
+	)
+	if c.hunger >= hungry {
+		vector.DrawFilledCircle(screen, float32(c.pos.x)+8, float32(c.pos.y)-2, 2, red, true)
+	}
+}
+
+// draw coop chickens and grain in the feeder above the chicken house
+func (g *Game) drawCoop(screen *ebiten.Image) {
+	house := g.chickenHouse()
+	if house == nil {
+		return
+	}
+	line := fmt.Sprintf("%d/%d  feed %d", g.housedChickens(), g.coopSize(), g.coopFeed)
+	addTextAt(screen, 8, line, white, house.pos.x, house.pos.y-10)
+}
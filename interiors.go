@@ -0,0 +1,294 @@
+package main
+
+import (
+	"image"
+
+	"github.com/eklownr/gorpg/interiors"
+	"github.com/eklownr/gorpg/sheets"
+	"github.com/eklownr/gorpg/tilemaps"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	interiorScene  = 4 // scene number while the Player is inside a building
+	floorLayer     = "floor"
+	wallsLayer     = "walls"
+	interiorCols   = 22   // columns in tileset_floor.png
+	roofFadeMax    = 0.65 // the roof is almost see through when the Player is behind it
+	roofFadeStep   = 0.08
+	npcTalkReach   = 40
+	furnitureSolid = 8 // the lower 8 pixels of furniture block the Player
+)
+
+// Interior is an enterable building with a tilemap scene, furniture, storage and villagers
+type Interior struct {
+	*interiors.Interior
+	tilemap   *tilemaps.TilemapJSON
+	furniture []*Objects
+	store     *StorageChest
+	npcs      []*NPC
+}
+
+// NPC is a villager inside a building
+type NPC struct {
+	*Characters
+	name, say string
+}
+
+// interiors from interiors.json. Furniture sprites are slices in the village sheet
+func (g *Game) loadInteriors(data *interiors.InteriorsJSON, sheet *sheets.SheetJSON, villageImg, npcImg *ebiten.Image) error {
+	for _, in := range data.Interiors {
+		tilemap, err := tilemaps.NewTilemapJSON(in.Map)
+		if err != nil {
+			return err
+		}
+		it := &Interior{Interior: in, tilemap: tilemap}
+		for _, f := range in.Furniture {
+			it.furniture = append(it.furniture, &Objects{
+				Sprite: &Sprite{
+					img:     villageImg,
+					pos:     Point{f.Pos[0], f.Pos[1]},
+					rectPos: sheetRect(sheet, f.Sprite),
+					active:  true,
+				},
+				variety: f.Sprite,
+			})
+		}
+		if in.Storage > 0 {
+			it.store = g.newChest(Point{in.StoragePos[0], in.StoragePos[1]})
+			it.store.scene = interiorScene
+			it.store.inv = NewInventory(in.Storage, stackSize)
+		}
+		for _, n := range in.NPCs {
+			it.npcs = append(it.npcs, &NPC{
+				Characters: &Characters{
+					Sprite: &Sprite{
+						img:    npcImg,
+						pos:    Point{n.Pos[0], n.Pos[1]},
+						active: true,
+						anim:   newRandomAnimator(characterAnim, "idle"),
+					},
+				},
+				name: n.Name,
+				say:  n.Say,
+			})
+		}
+		g.interiors = append(g.interiors, it)
+	}
+	return nil
+}
+
+// Player body, the same box the collisions use
+func playerBox(c *Characters) image.Rectangle {
+	return image.Rect(int(c.pos.x+imgSize/4), int(c.pos.y+imgSize/4), int(c.pos.x+imgSize/2), int(c.pos.y+imgSize/2))
+}
+
+// walk into the door of a house. The door only work when a house stand there
+func (g *Game) enterInterior() bool {
+	if g.inside != nil || g.scene != 0 {
+		return false
+	}
+	box := playerBox(g.Player)
+	for _, in := range g.interiors {
+		door := in.DoorRect()
+		if !box.Overlaps(door) || !g.houseAt(door) {
+			continue
+		}
+		g.closeChest()
+		g.inside = in
+		g.outsidePos = g.Player.prePos
+		g.outsideScene = g.scene
+		g.setScene(interiorScene)
+		g.Player.pos = Point{in.Spawn[0], in.Spawn[1]}
+		g.Player.prePos = g.Player.pos
+		return true
+	}
+	return false
+}
+
+// an active house cover r
+func (g *Game) houseAt(r image.Rectangle) bool {
+	for _, house := range g.house {
+		if house.active && r.In(houseRect(house)) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk out the exit, the Player is back in front of the door
+func (g *Game) leaveInterior() {
+	if g.inside == nil {
+		return
+	}
+	g.closeChest()
+	g.inside = nil
+	g.setScene(g.outsideScene)
+	g.Player.pos = g.outsidePos
+	g.Player.prePos = g.Player.pos
+}
+
+// walls and furniture block the Player, the exit take the Player outside
+func (g *Game) updateInterior() {
+	in := g.inside
+	box := playerBox(g.Player)
+	if box.Overlaps(in.ExitRect()) {
+		g.leaveInterior()
+		return
+	}
+	if g.interiorBlocked(box) {
+		g.Player.pos = g.Player.prePos
+	}
+	for _, npc := range in.npcs {
+		npc.anim.Update()
+	}
+}
+
+// box is in a wall, outside the floor or in furniture
+func (g *Game) interiorBlocked(box image.Rectangle) bool {
+	in := g.inside
+	floor := in.tilemap.Layer(floorLayer)
+	walls := in.tilemap.Layer(wallsLayer)
+	for _, p := range []image.Point{box.Min, {box.Max.X - 1, box.Min.Y}, {box.Min.X, box.Max.Y - 1}, box.Max.Sub(image.Pt(1, 1))} {
+		x, y := p.X/tileSize, p.Y/tileSize
+		if floor == nil || !floor.Inside(x, y) || floor.Tile(x, y) == 0 {
+			return true
+		}
+		if walls != nil && walls.Tile(x, y) != 0 {
+			return true
+		}
+	}
+	for _, f := range in.furniture {
+		r := image.Rect(int(f.pos.x), int(f.pos.y), int(f.pos.x)+f.rectPos.Dx(), int(f.pos.y)+f.rectPos.Dy())
+		r.Min.Y = r.Max.Y - furnitureSolid
+		if r.Overlaps(box) {
+			return true
+		}
+	}
+	return false
+}
+
+// villager next to the Player
+func (g *Game) nearNPC() *NPC {
+	for _, npc := range g.inside.npcs {
+		dx, dy := npc.pos.x-g.Player.pos.x, npc.pos.y-g.Player.pos.y
+		if dx*dx+dy*dy < npcTalkReach*npcTalkReach {
+			return npc
+		}
+	}
+	return nil
+}
+
+// draw the interior tile layers, the tiles are not seasonal
+func (g *Game) drawInteriorGround(screen *ebiten.Image) {
+	op := &ebiten.DrawImageOptions{}
+	for _, layer := range g.inside.tilemap.Layers {
+		for index, id := range layer.Data {
+			if id == 0 {
+				continue
+			}
+			x := index % layer.Width * tileSize
+			y := index / layer.Width * tileSize
+			srcX := (id - 1) % interiorCols * tileSize
+			srcY := (id - 1) / interiorCols * tileSize
+			op.GeoM.Reset()
+			op.GeoM.Translate(float64(x), float64(y))
+			screen.DrawImage(subImage(g.tilemapImg, image.Rect(srcX, srcY, srcX+tileSize, srcY+tileSize)), op)
+		}
+	}
+}
+
+// queue furniture, storage and villagers inside the building
+func (g *Game) queueInterior(q *RenderQueue) {
+	in := g.inside
+	for _, f := range in.furniture {
+		q.Add(LayerWorld, f.pos.y+float64(f.rectPos.Dy()), func(screen *ebiten.Image) {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(f.pos.x, f.pos.y)
+			screen.DrawImage(subImage(f.img, f.rectPos), op)
+		})
+	}
+	if in.store != nil {
+		q.Add(LayerWorld, in.store.pos.y+16, func(screen *ebiten.Image) { g.drawChest(screen, in.store) })
+	}
+	for _, npc := range in.npcs {
+		q.Add(LayerWorld, footY(npc.Characters), func(screen *ebiten.Image) {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(npc.pos.x, npc.pos.y)
+			screen.DrawImage(subImage(npc.img, npc.anim.Rect()), op)
+		})
+	}
+	if npc := g.nearNPC(); npc != nil { // the villager talk when the Player is close
+		q.Add(LayerUI, 0, func(screen *ebiten.Image) {
+			addText(screen, 10, npc.name+": "+npc.say, white, npc.pos.x*2+imgSize, npc.pos.y*2)
+		})
+	}
+	q.Add(LayerUI, 0, func(screen *ebiten.Image) { addText(screen, 10, in.Name, yellow, screenWidth, 24) })
+}
+
+// house sprite on the screen
+func houseRect(house *Objects) image.Rectangle {
+	return image.Rect(int(house.pos.x), int(house.pos.y), int(house.pos.x)+house.rectPos.Dx(), int(house.pos.y)+house.rectPos.Dy())
+}
+
+// houses fade the roof, budda and the chicken house don't
+func hasRoof(house *Objects) bool {
+	switch house.variety {
+	case "house", "small_house", "new_house", "new_house_small":
+		return true
+	}
+	return false
+}
+
+// the upper half of the house is the roof
+func roofRect(r image.Rectangle) image.Rectangle {
+	r.Max.Y = r.Min.Y + r.Dy()/2
+	return r
+}
+
+// fade toward see through while the Player is behind the roof
+func roofFade(fade float32, roof image.Rectangle, player image.Rectangle) float32 {
+	if roof.Overlaps(player) {
+		return min(1, fade+roofFadeStep)
+	}
+	return max(0, fade-roofFadeStep)
+}
+
+// fade roofs of houses and buildings the Player walk behind
+func (g *Game) updateRoofs() {
+	box := playerBox(g.Player)
+	for _, house := range g.house {
+		if !hasRoof(house) {
+			continue
+		}
+		house.roof = roofFade(house.roof, roofRect(houseRect(house)), box)
+	}
+	for _, b := range g.buildingSites {
+		if !b.done() {
+			continue
+		}
+		b.roof = roofFade(b.roof, roofRect(b.footprint()), box)
+	}
+}
+
+// queue sprite part src at pos, the walls in the world at foot y and the roof
+// over the world with the roof fade
+func queueWithRoof(q *RenderQueue, img *ebiten.Image, src image.Rectangle, pos Point, y float64, fade float32) {
+	roof := roofRect(src)
+	walls := src
+	walls.Min.Y = roof.Max.Y
+	q.Add(LayerWorld, y, func(screen *ebiten.Image) {
+		drawPart(screen, img, walls, Point{pos.x, pos.y + float64(roof.Dy())}, 1)
+	})
+	q.Add(LayerOverhead, 0, func(screen *ebiten.Image) {
+		drawPart(screen, img, roof, pos, 1-fade*roofFadeMax)
+	})
+}
+
+// draw sprite part src at pos with alpha
+func drawPart(screen, img *ebiten.Image, src image.Rectangle, pos Point, alpha float32) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(pos.x, pos.y)
+	op.ColorScale.ScaleAlpha(alpha)
+	screen.DrawImage(subImage(img, src), op)
+}
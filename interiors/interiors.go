@@ -0,0 +1,80 @@
+package interiors
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+)
+
+// Furniture is a sprite from the village sprite sheet standing in the room
+type Furniture struct {
+	Sprite string     `json:"sprite"` // slice name in village_old.json
+	Pos    [2]float64 `json:"pos"`
+}
+
+// NPC is a villager living in the building
+type NPC struct {
+	Name string     `json:"name"`
+	Pos  [2]float64 `json:"pos"`
+	Say  string     `json:"say"`
+}
+
+// Interior is the inside of a house. Walking into Door outside load the
+// Map scene with the Player at Spawn, walking into Exit go back outside.
+// Rects are x0, y0, x1, y1 in screen pixels.
+type Interior struct {
+	Name       string      `json:"name"`
+	Door       [4]int      `json:"door"`
+	Map        string      `json:"map"`
+	Spawn      [2]float64  `json:"spawn"`
+	Exit       [4]int      `json:"exit"`
+	Storage    int         `json:"storage"` // slots in the storage, 0 for no storage
+	StoragePos [2]float64  `json:"storagePos"`
+	Furniture  []Furniture `json:"furniture"`
+	NPCs       []NPC       `json:"npcs"`
+}
+
+type InteriorsJSON struct {
+	Interiors []*Interior `json:"interiors"`
+	byName    map[string]*Interior
+}
+
+func NewInteriorsJSON(filepath string) (*InteriorsJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var interiorsJSON InteriorsJSON
+	err = json.Unmarshal(content, &interiorsJSON)
+	if err != nil {
+		return nil, err
+	}
+	interiorsJSON.byName = make(map[string]*Interior)
+	for _, in := range interiorsJSON.Interiors {
+		if in.Map == "" || in.DoorRect().Empty() || in.ExitRect().Empty() {
+			return nil, fmt.Errorf("interiors: %s needs map, door and exit", in.Name)
+		}
+		if interiorsJSON.byName[in.Name] != nil {
+			return nil, fmt.Errorf("interiors: %s is defined twice", in.Name)
+		}
+		interiorsJSON.byName[in.Name] = in
+	}
+	return &interiorsJSON, nil
+}
+
+// Get interior by name
+func (i *InteriorsJSON) Get(name string) (*Interior, bool) {
+	in, ok := i.byName[name]
+	return in, ok
+}
+
+// DoorRect outside the house
+func (in *Interior) DoorRect() image.Rectangle {
+	return image.Rect(in.Door[0], in.Door[1], in.Door[2], in.Door[3])
+}
+
+// ExitRect inside the room
+func (in *Interior) ExitRect() image.Rectangle {
+	return image.Rect(in.Exit[0], in.Exit[1], in.Exit[2], in.Exit[3])
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"image"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	coinItem      = "coin"
+	eggItem       = egg
+	chickenItem   = chicken
+	seedSuffix    = "_seed"
+	hotbarSlots   = 9  // first slots in the Player inventory
+	playerSlots   = 18 // hotbar and bag
+	stackSize     = 99 // max items in one slot
+	slotSize      = 20 // slot size on screen
+	inventoryCols = 9
+)
+
+// ItemStack is one slot in an inventory. An empty slot has Count 0
+type ItemStack struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+// Inventory is a fixed number of slots with item stacks
+type Inventory struct {
+	slots     []ItemStack
+	stackSize int
+}
+
+func NewInventory(capacity, stackSize int) *Inventory {
+	return &Inventory{
+		slots:     make([]ItemStack, capacity),
+		stackSize: stackSize,
+	}
+}
+
+// Count all items of item in the inventory
+func (inv *Inventory) Count(item string) int {
+	count := 0
+	for _, s := range inv.slots {
+		if s.Item == item {
+			count += s.Count
+		}
+	}
+	return count
+}
+
+// Space left for item, in stacks with the same item and empty slots
+func (inv *Inventory) Space(item string) int {
+	space := 0
+	for _, s := range inv.slots {
+		if s.Count == 0 || s.Item == item {
+			space += inv.stackSize - s.Count
+		}
+	}
+	return space
+}
+
+// Add n items, first to stacks with the same item then to empty slots.
+// Return how many items were added
+func (inv *Inventory) Add(item string, n int) int {
+	added := 0
+	for _, fill := range []bool{false, true} {
+		for i := range inv.slots {
+			s := &inv.slots[i]
+			if added == n || (s.Count > 0 && s.Item != item) || (s.Count == 0) != fill {
+				continue
+			}
+			put := min(n-added, inv.stackSize-s.Count)
+			s.Item = item
+			s.Count += put
+			added += put
+		}
+	}
+	return added
+}
+
+// Fits when all stacks can be added at once
+func (inv *Inventory) Fits(stacks ...ItemStack) bool {
+	trial := &Inventory{slots: slices.Clone(inv.slots), stackSize: inv.stackSize}
+	for _, s := range stacks {
+		if trial.Add(s.Item, s.Count) < s.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// Remove n items, return how many items were removed
+func (inv *Inventory) Remove(item string, n int) int {
+	removed := 0
+	for i := len(inv.slots) - 1; i >= 0 && removed < n; i-- {
+		s := &inv.slots[i]
+		if s.Item != item || s.Count == 0 {
+			continue
+		}
+		take := min(n-removed, s.Count)
+		s.Count -= take
+		removed += take
+		if s.Count == 0 {
+			s.Item = ""
+		}
+	}
+	return removed
+}
+
+// RemoveAt remove n items from slot i
+func (inv *Inventory) RemoveAt(i, n int) {
+	s := &inv.slots[i]
+	s.Count -= min(n, s.Count)
+	if s.Count == 0 {
+		*s = ItemStack{}
+	}
+}
+
+// Total number of items where match(item) is true
+func (inv *Inventory) Total(match func(item string) bool) int {
+	total := 0
+	for _, s := range inv.slots {
+		if s.Count > 0 && match(s.Item) {
+			total += s.Count
+		}
+	}
+	return total
+}
+
+// Clear remove all items where match(item) is true
+func (inv *Inventory) Clear(match func(item string) bool) {
+	for i, s := range inv.slots {
+		if s.Count > 0 && match(s.Item) {
+			inv.slots[i] = ItemStack{}
+		}
+	}
+}
+
+// Slot at index i
+func (inv *Inventory) Slot(i int) ItemStack {
+	return inv.slots[i]
+}
+
+// Stacks return a copy of all slots, used when saving
+func (inv *Inventory) Stacks() []ItemStack {
+	return append([]ItemStack(nil), inv.slots...)
+}
+
+// SetStacks replace the slots, used when loading
+func (inv *Inventory) SetStacks(stacks []ItemStack) {
+	clear(inv.slots)
+	copy(inv.slots, stacks)
+}
+
+// move stack from slot i in inventory a to slot j in inventory b.
+// Same item is merged, different items change place
+func moveStack(a *Inventory, i int, b *Inventory, j int) {
+	from, to := &a.slots[i], &b.slots[j]
+	if from == to {
+		return
+	}
+	if to.Count > 0 && to.Item == from.Item {
+		put := min(from.Count, b.stackSize-to.Count)
+		to.Count += put
+		from.Count -= put
+		if from.Count == 0 {
+			*from = ItemStack{}
+		}
+		return
+	}
+	*from, *to = *to, *from
+}
+
+// seed item for crop
+func seedItem(crop string) string {
+	return crop + seedSuffix
+}
+
+// crop for a seed item
+func seedCrop(item string) (string, bool) {
+	return strings.CutSuffix(item, seedSuffix)
+}
+
+// crops are the items in the basket
+func (g *Game) isCrop(item string) bool {
+	_, ok := g.crops.Get(item)
+	return ok
+}
+
+// number of crops in the Player basket
+func (g *Game) basketCount() int {
+	return g.Player.inv.Total(g.isCrop)
+}
+
+// item in the selected hotbar slot
+func (g *Game) activeItem() ItemStack {
+	return g.Player.inv.Slot(g.hotbarSel)
+}
+
+// source image and rectangle for the item icon
+func (g *Game) itemIcon(item string) (*ebiten.Image, image.Rectangle) {
+	if name, ok := seedCrop(item); ok {
+		if crop, ok := g.crops.Get(name); ok {
+			return g.plantImg, image.Rect(16*crop.SeedIcon, 16*crop.Row, 16*crop.SeedIcon+16, 16*crop.Row+16)
+		}
+	}
+	if crop, ok := g.crops.Get(item); ok {
+		return g.plantImg, image.Rect(16*crop.Icon, 16*crop.Row, 16*crop.Icon+16, 16*crop.Row+16)
+	}
+	switch item {
+	case coinItem:
+		return g.coinImg, image.Rect(0, 0, 10, 10)
+	case eggItem:
+		return g.eggImg, image.Rect(0, 0, 16, 16)
+	case chickenItem:
+		return g.chickenImg, image.Rect(0, 0, 16, 16)
+	case chestItem:
+		return g.chestImg, image.Rect(16, 16, 32, 32)
+	case woodItem:
+		return g.biomImg, image.Rect(80, 32, 96, 48)
+	case stoneItem:
+		return g.biomImg, image.Rect(128, 16, 144, 32)
+	}
+	return nil, image.Rectangle{}
+}
+
+// invView is a grid of inventory slots on the screen
+type invView struct {
+	inv         *Inventory
+	first, last int // slots in the view
+	x, y        float64
+}
+
+// slot index at screen position, -1 if outside
+func (v invView) slotAt(x, y int) int {
+	for i := v.first; i < v.last; i++ {
+		if image.Pt(x, y).In(v.slotRect(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// slot rectangle on screen
+func (v invView) slotRect(i int) image.Rectangle {
+	n := i - v.first
+	x := int(v.x) + n%inventoryCols*slotSize
+	y := int(v.y) + n/inventoryCols*slotSize
+	return image.Rect(x, y, x+slotSize, y+slotSize)
+}
+
+// hotbar at the bottom of the screen
+func (g *Game) hotbarView() invView {
+	return invView{g.Player.inv, 0, hotbarSlots, (screenWidth - inventoryCols*slotSize) / 2, screenHeight - slotSize - 4}
+}
+
+// bag above the hotbar, open with key: i
+func (g *Game) bagView() invView {
+	return invView{g.Player.inv, hotbarSlots, playerSlots, (screenWidth - inventoryCols*slotSize) / 2, screenHeight - slotSize*3 - 8}
+}
+
+// inventory views on screen, top first
+func (g *Game) invViews() []invView {
+	views := []invView{g.hotbarView()}
+	if g.inventoryOpen || g.openChest != nil {
+		views = append(views, g.bagView())
+	}
+	if g.openChest != nil {
+		views = append(views, g.chestView())
+	}
+	return views
+}
+
+// I key open or close the bag
+func (g *Game) inventoryKey() {
+	g.inventoryOpen = !g.inventoryOpen
+}
+
+// select hotbar slot with mouse wheel or [ ] keys, drag and drop stacks with the mouse
+func (g *Game) updateInventory() {
+	_, wheel := ebiten.Wheel()
+	if wheel < 0 || inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+		g.hotbarSel = (g.hotbarSel + 1) % hotbarSlots
+	}
+	if wheel > 0 || inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+		g.hotbarSel = (g.hotbarSel + hotbarSlots - 1) % hotbarSlots
+	}
+
+	x, y := ebiten.CursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		for _, v := range g.invViews() {
+			if i := v.slotAt(x, y); i >= 0 {
+				if v.inv == g.Player.inv && i < hotbarSlots {
+					g.hotbarSel = i
+				}
+				if v.inv.Slot(i).Count > 0 {
+					g.drag = &dragStack{v.inv, i}
+				}
+			}
+		}
+	}
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && g.drag != nil {
+		for _, v := range g.invViews() {
+			i := v.slotAt(x, y)
+			if i < 0 {
+				continue
+			}
+			if v.inv == g.Player.inv && g.drag.inv != g.Player.inv && g.drag.inv.Slot(g.drag.slot).Item == coinItem {
+				g.moveCoins(g.drag.inv, g.drag.slot, i)
+			} else {
+				moveStack(g.drag.inv, g.drag.slot, v.inv, i)
+			}
+		}
+		g.drag = nil
+	}
+}
+
+// coins the Player can still put in the wallet
+func (g *Game) walletRoom() int {
+	return max(0, g.Player.wallet-g.Player.inv.Count(coinItem))
+}
+
+// move coins from slot i in inventory a to slot j in the Player inventory,
+// only as many as fit in the wallet
+func (g *Game) moveCoins(a *Inventory, i, j int) {
+	from, to := &a.slots[i], &g.Player.inv.slots[j]
+	room := g.walletRoom()
+	if from.Count <= room {
+		moveStack(a, i, g.Player.inv, j)
+		return
+	}
+	if room == 0 || to.Count > 0 && to.Item != coinItem {
+		Publish(g, ActionFailed{"Wallet full", coinItem})
+		return
+	}
+	put := min(room, g.Player.inv.stackSize-to.Count)
+	to.Item = coinItem
+	to.Count += put
+	from.Count -= put
+}
+
+// dragStack is the stack the mouse is dragging
+type dragStack struct {
+	inv  *Inventory
+	slot int
+}
+
+// draw one item icon with the count
+func (g *Game) drawStack(screen *ebiten.Image, s ItemStack, x, y float64) {
+	img, rect := g.itemIcon(s.Item)
+	if img == nil || s.Count == 0 {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x+float64(slotSize-rect.Dx())/2, y+float64(slotSize-rect.Dy())/2)
+	screen.DrawImage(subImage(img, rect), op)
+	if s.Count > 1 {
+		addTextAt(screen, 8, strconv.Itoa(s.Count), white, x+slotSize-10, y+slotSize-10)
+	}
+}
+
+// draw all slots in the view
+func (g *Game) drawInvView(screen *ebiten.Image, v invView) {
+	for i := v.first; i < v.last; i++ {
+		r := v.slotRect(i)
+		vector.DrawFilledRect(screen, float32(r.Min.X), float32(r.Min.Y), slotSize, slotSize, blue_transp, true)
+		border := blue_rect
+		if v.inv == g.Player.inv && i == g.hotbarSel {
+			border = yellow
+		}
+		vector.StrokeRect(screen, float32(r.Min.X), float32(r.Min.Y), slotSize, slotSize, 1, border, true)
+		if g.drag != nil && g.drag.inv == v.inv && g.drag.slot == i {
+			continue // stack is drawn at the mouse
+		}
+		g.drawStack(screen, v.inv.Slot(i), float64(r.Min.X), float64(r.Min.Y))
+	}
+}
+
+// draw hotbar, bag and the dragged stack
+func (g *Game) drawInventory(screen *ebiten.Image) {
+	for _, v := range g.invViews() {
+		g.drawInvView(screen, v)
+	}
+	if s := g.activeItem(); s.Count > 0 {
+		v := g.hotbarView()
+		addTextAt(screen, 10, strings.ReplaceAll(s.Item, "_", " "), white, v.x, v.y-14)
+	}
+	if g.drag != nil {
+		x, y := ebiten.CursorPosition()
+		g.drawStack(screen, g.drag.inv.Slot(g.drag.slot), float64(x-slotSize/2), float64(y-slotSize/2))
+	}
+}
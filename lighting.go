@@ -0,0 +1,168 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	lightSize   = 64 // light sprite size
+	fireFrame   = 8  // frame width in Fire.png
+	fireFirst   = 3  // first frame in the burning loop
+	fireFrames  = 5  // frames in the burning loop
+	fireTicks   = 8  // ticks for every fire frame
+	lightsOnAt  = 0.15
+	windowLight = 0.7 // window light scale
+)
+
+// ambient light at time of day in minutes. Colors in between are blended
+var ambient = []struct {
+	minute int
+	c      [3]float32
+}{
+	{0, [3]float32{0.22, 0.25, 0.45}},
+	{5 * 60, [3]float32{0.22, 0.25, 0.45}},
+	{6*60 + 30, [3]float32{0.85, 0.7, 0.65}},
+	{8 * 60, [3]float32{1, 1, 1}},
+	{18 * 60, [3]float32{1, 1, 1}},
+	{19*60 + 30, [3]float32{0.9, 0.6, 0.45}},
+	{21 * 60, [3]float32{0.22, 0.25, 0.45}},
+	{dayMinutes, [3]float32{0.22, 0.25, 0.45}},
+}
+
+// light map is multiplied with the screen
+var blendMultiply = ebiten.Blend{
+	BlendFactorSourceRGB:        ebiten.BlendFactorDestinationColor,
+	BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+	BlendFactorDestinationRGB:   ebiten.BlendFactorZero,
+	BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+	BlendOperationRGB:           ebiten.BlendOperationAdd,
+	BlendOperationAlpha:         ebiten.BlendOperationAdd,
+}
+
+// light source on the light map
+type light struct {
+	pos    Point
+	radius float64
+	c      color.RGBA
+}
+
+// ambient color at time of day
+func ambientColor(minute int) [3]float32 {
+	for i := 1; i < len(ambient); i++ {
+		a, b := ambient[i-1], ambient[i]
+		if minute < b.minute {
+			t := float32(minute-a.minute) / float32(b.minute-a.minute)
+			var c [3]float32
+			for j := range c {
+				c[j] = a.c[j] + (b.c[j]-a.c[j])*t
+			}
+			return c
+		}
+	}
+	return ambient[len(ambient)-1].c
+}
+
+// white radial light, bright in the middle
+func newLightImg() *ebiten.Image {
+	pix := make([]byte, lightSize*lightSize*4)
+	for y := range lightSize {
+		for x := range lightSize {
+			d := math.Hypot(float64(x)-lightSize/2+0.5, float64(y)-lightSize/2+0.5) / (lightSize / 2)
+			a := byte(255 * math.Max(0, 1-d) * math.Max(0, 1-d))
+			i := (y*lightSize + x) * 4
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = a, a, a, a
+		}
+	}
+	img := ebiten.NewImage(lightSize, lightSize)
+	img.WritePixels(pix)
+	return img
+}
+
+// campfire and house windows light up the night
+func (g *Game) lights() []light {
+	var lights []light
+	for _, f := range g.fires {
+		if f.scene != g.scene {
+			continue
+		}
+		lights = append(lights, light{Point{f.pos.x + fireFrame/2, f.pos.y + 6}, 56, color.RGBA{255, 170, 80, 255}})
+	}
+	for _, house := range g.house {
+		if house.active && house.variety != "budda" {
+			lights = append(lights, g.windowLight(house.pos, house.rectPos))
+		}
+	}
+	if g.scene == 0 {
+		for _, b := range g.buildingSites {
+			if b.done() {
+				lights = append(lights, g.windowLight(b.pos, b.rectPos))
+			}
+		}
+	}
+	return lights
+}
+
+// warm light from the windows of a house
+func (g *Game) windowLight(pos Point, rect image.Rectangle) light {
+	center := Point{pos.x + float64(rect.Dx())/2, pos.y + float64(rect.Dy())*0.6}
+	return light{center, float64(rect.Dx()) * windowLight, color.RGBA{255, 210, 120, 255}}
+}
+
+// Campfire is a fire with embers, seen and lit only in its scene
+type Campfire struct {
+	*Sprite
+	scene  int
+	embers *Emitter
+}
+
+// animate the campfires, the embers only rise in the scene of the fire
+func (g *Game) updateFires() {
+	for _, f := range g.fires {
+		f.anim.Update()
+		f.embers.active = f.scene == g.scene
+	}
+}
+
+// draw campfire
+func (g *Game) drawFire(screen *ebiten.Image, f *Campfire) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(f.pos.x, f.pos.y)
+	screen.DrawImage(subImage(f.img, f.anim.Rect()), op)
+}
+
+// color the world by time of day and weather. Lights brighten the dark on an offscreen light map
+func (g *Game) drawLighting(screen *ebiten.Image) {
+	c := ambientColor(g.clock.TimeOfDay())
+	for i := range c {
+		c[i] *= g.weatherDim()
+	}
+	if c == [3]float32{1, 1, 1} {
+		return // full daylight
+	}
+	if g.lightMap == nil {
+		g.lightMap = ebiten.NewImage(screen.Bounds().Dx(), screen.Bounds().Dy())
+	}
+	g.lightMap.Fill(color.RGBA{uint8(c[0] * 255), uint8(c[1] * 255), uint8(c[2] * 255), 255})
+
+	dark := 1 - (c[0]+c[1]+c[2])/3
+	if dark > lightsOnAt {
+		for _, l := range g.lights() {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(-lightSize/2, -lightSize/2)
+			op.GeoM.Scale(l.radius*2/lightSize, l.radius*2/lightSize)
+			op.GeoM.Translate(l.pos.x, l.pos.y)
+			k := min(1, dark*1.5)
+			op.ColorScale.ScaleWithColor(l.c)
+			op.ColorScale.Scale(k, k, k, k)
+			op.Blend = ebiten.BlendLighter
+			g.lightMap.DrawImage(g.lightImg, op)
+		}
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.Blend = blendMultiply
+	screen.DrawImage(g.lightMap, op)
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	sellDrop    = 0.9  // price drop for every item the Player sell
+	buyRise     = 1.05 // price rise for every item the Player buy
+	buyMarkup   = 1.5  // budda sell to the Player at a higher price
+	minPrice    = 0.3  // price never drop below base price * minPrice
+	priceReturn = 0.25 // every day the price move back to the base price
+	priceDrift  = 0.1  // random daily price change
+	marketLog   = 50   // transactions in the log
+	chestPrice  = 8
+	woodPrice   = 1
+	stonePrice  = 1
+	eggPrice    = 2
+)
+
+// MarketItem is one item the budda trade
+type MarketItem struct {
+	item      string
+	basePrice int
+	price     float64 // current price when the Player sell
+	canSell   bool    // Player can sell to the budda
+	canBuy    bool    // Player can buy from the budda
+}
+
+// Transaction in the market log
+type Transaction struct {
+	day   int
+	item  string
+	price int
+	buy   bool
+}
+
+// Market with prices that change with supply and demand
+type Market struct {
+	items    []*MarketItem
+	log      []Transaction
+	selected int
+}
+
+// market with crops, seeds, eggs, chests and building materials
+func (g *Game) newMarket() *Market {
+	m := &Market{}
+	for _, crop := range g.crops.Crops {
+		m.items = append(m.items, &MarketItem{item: crop.Name, basePrice: crop.SellPrice, canSell: true, canBuy: true})
+	}
+	for _, crop := range g.crops.Crops {
+		m.items = append(m.items, &MarketItem{item: seedItem(crop.Name), basePrice: crop.SeedPrice, canBuy: true})
+	}
+	m.items = append(m.items, &MarketItem{item: eggItem, basePrice: eggPrice, canSell: true})
+	m.items = append(m.items, &MarketItem{item: chestItem, basePrice: chestPrice, canBuy: true})
+	m.items = append(m.items, &MarketItem{item: woodItem, basePrice: woodPrice, canBuy: true})
+	m.items = append(m.items, &MarketItem{item: stoneItem, basePrice: stonePrice, canBuy: true})
+	for _, it := range m.items {
+		it.price = float64(it.basePrice)
+	}
+	return m
+}
+
+// sell price, at least one coin
+func (it *MarketItem) sellPrice() int {
+	return max(1, int(math.Round(it.price)))
+}
+
+// buy price is higher than sell price
+func (it *MarketItem) buyPrice() int {
+	return max(1, int(math.Ceil(it.price*buyMarkup)))
+}
+
+// prices move back to the base price with some random drift
+func (m *Market) newDay() {
+	for _, it := range m.items {
+		base := float64(it.basePrice)
+		it.price += (base - it.price) * priceReturn
+		it.price *= 1 + (rand.Float64()*2-1)*priceDrift
+		it.price = max(it.price, base*minPrice)
+	}
+}
+
+// add transaction to the log, oldest is removed
+func (m *Market) record(t Transaction) {
+	m.log = append(m.log, t)
+	if len(m.log) > marketLog {
+		m.log = m.log[1:]
+	}
+}
+
+// sell one item to the budda. Return false if the Player can't sell
+func (g *Game) sellItem(it *MarketItem) bool {
+	if !it.canSell || g.Player.inv.Count(it.item) == 0 {
+		return false
+	}
+	if g.Player.inv.Count(coinItem) >= g.Player.wallet {
+		Publish(g, ActionFailed{"Wallet full", coinItem})
+		return false
+	}
+	price := it.sellPrice()
+	g.Player.inv.Remove(it.item, 1)
+	g.Player.inv.Add(coinItem, price)
+	it.price = max(it.price*sellDrop, float64(it.basePrice)*minPrice)
+	g.market.record(Transaction{g.clock.Day(), it.item, price, false})
+	Publish(g, ItemSold{it.item, price})
+	Publish(g, CoinEarned{price, Point{g.Player.pos.x + imgSize/2, g.Player.pos.y + imgSize/4}})
+	return true
+}
+
+// buy one item from the budda. Return false if the Player can't buy
+func (g *Game) buyItem(it *MarketItem) bool {
+	price := it.buyPrice()
+	if !it.canBuy {
+		return false
+	}
+	if g.Player.inv.Count(coinItem) < price {
+		Publish(g, ActionFailed{"Not enough coins", coinItem})
+		return false
+	}
+	if g.Player.inv.Space(it.item) == 0 {
+		Publish(g, ActionFailed{"Bag full", it.item})
+		return false
+	}
+	g.Player.inv.Remove(coinItem, price)
+	g.Player.inv.Add(it.item, 1)
+	it.price *= buyRise
+	g.market.record(Transaction{g.clock.Day(), it.item, price, true})
+	playSound(audioCoin)
+	return true
+}
+
+// the budda give a chest and a coin for every egg sold
+func subscribeMarket(b *EventBus) {
+	Subscribe(b, func(g *Game, e ItemSold) {
+		if e.Item != eggItem {
+			return
+		}
+		g.buddaSpawnItems[chest].active = true
+		g.buddaSpawnItems[chest].pickable = true
+		g.buddaSpawnItems[chest].picked = false
+		g.coins[0].active = true
+		g.coins[0].picked = false
+	})
+}
+
+// open market when the Player visit the budda
+func (g *Game) openMarket() {
+	g.marketOpen = true
+	g.infoBoxSpite.active = false
+}
+
+// market is open, the game wait for the Player to trade.
+// Up/down select item, the sell and buy keys trade, Esc, the pause or the interact key close.
+// Mouse click on sell or buy price
+func (g *Game) updateMarket() {
+	m := g.market
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || keyJustPressed(actPause) || keyJustPressed(actInteract) {
+		g.marketOpen = false
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || keyJustPressed(actDown) {
+		m.selected = (m.selected + 1) % len(m.items)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || keyJustPressed(actUp) {
+		m.selected = (m.selected + len(m.items) - 1) % len(m.items)
+	}
+	if keyJustPressed(actSell) {
+		g.sellItem(m.items[m.selected])
+	}
+	if keyJustPressed(actBuy) {
+		g.buyItem(m.items[m.selected])
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		for i, it := range m.items {
+			if image.Pt(x, y).In(marketCell(i, marketSellCol)) {
+				m.selected = i
+				g.sellItem(it)
+			}
+			if image.Pt(x, y).In(marketCell(i, marketBuyCol)) {
+				m.selected = i
+				g.buyItem(it)
+			}
+		}
+	}
+}
+
+// market panel layout
+const (
+	marketX       = 40
+	marketY       = 24
+	marketW       = 560
+	marketRow     = 16
+	marketSellCol = 190
+	marketBuyCol  = 260
+	marketHaveCol = 330
+	marketLogCol  = 380
+)
+
+// cell in a market row, used for mouse clicks
+func marketCell(row, col int) image.Rectangle {
+	y := marketY + 24 + row*marketRow
+	return image.Rect(marketX+col-4, y, marketX+col+56, y+marketRow)
+}
+
+// draw market panel with prices, what the Player have and the transaction log
+func (g *Game) drawMarket(screen *ebiten.Image) {
+	if !g.marketOpen {
+		return
+	}
+	m := g.market
+	h := float32(24 + len(m.items)*marketRow + 24)
+	vector.DrawFilledRect(screen, marketX, marketY, marketW, h, blue_transp, true)
+	vector.StrokeRect(screen, marketX, marketY, marketW, h, 1, yellow, true)
+	addTextAt(screen, 10, "Budda market", yellow, marketX+8, marketY+6)
+	addTextAt(screen, 10, "sell", yellow, marketX+marketSellCol, marketY+6)
+	addTextAt(screen, 10, "buy", yellow, marketX+marketBuyCol, marketY+6)
+	addTextAt(screen, 10, "have", yellow, marketX+marketHaveCol, marketY+6)
+	addTextAt(screen, 10, "log", yellow, marketX+marketLogCol, marketY+6)
+
+	for i, it := range m.items {
+		y := float64(marketY + 24 + i*marketRow)
+		if i == m.selected {
+			vector.DrawFilledRect(screen, marketX+2, float32(y), marketLogCol-8, marketRow, blue_rect, true)
+		}
+		if img, rect := g.itemIcon(it.item); img != nil {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(marketX+8, y)
+			screen.DrawImage(subImage(img, rect), op)
+		}
+		addTextAt(screen, 10, it.item, white, marketX+30, y+2)
+		if it.canSell {
+			addTextAt(screen, 10, fmt.Sprintf("%d coin", it.sellPrice()), priceColor(it), marketX+marketSellCol, y+2)
+		}
+		if it.canBuy {
+			addTextAt(screen, 10, fmt.Sprintf("%d coin", it.buyPrice()), white, marketX+marketBuyCol, y+2)
+		}
+		addTextAt(screen, 10, fmt.Sprint(g.Player.inv.Count(it.item)), white, marketX+marketHaveCol, y+2)
+	}
+
+	// newest transactions first
+	for i := 0; i < len(m.log) && i < len(m.items); i++ {
+		t := m.log[len(m.log)-1-i]
+		line := fmt.Sprintf("day %d sold %s +%d", t.day, t.item, t.price)
+		if t.buy {
+			line = fmt.Sprintf("day %d bought %s -%d", t.day, t.item, t.price)
+		}
+		addTextAt(screen, 8, line, white, marketX+marketLogCol, float64(marketY+26+i*marketRow))
+	}
+	keys := fmt.Sprintf("%s sell  %s buy  %s close", keyOf(actSell), keyOf(actBuy), keyOf(actPause))
+	addTextAt(screen, 10, keys, yellow, marketX+8, float64(marketY)+float64(h)-16)
+}
+
+// price below base price is red, above is green
+func priceColor(it *MarketItem) color.Color {
+	switch {
+	case it.sellPrice() < it.basePrice:
+		return red
+	case it.sellPrice() > it.basePrice:
+		return green
+	}
+	return white
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"slices"
+
+	"github.com/ebitenui/ebitenui"
+	eimage "github.com/ebitenui/ebitenui/image"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// menuScreen is the open menu, menuNone while playing
+type menuScreen int
+
+const (
+	menuNone menuScreen = iota
+	menuTitle
+	menuPause
+	menuOptions
+	menuKeys
+	menuAchievements
+)
+
+const (
+	menuTextSize  = 12
+	menuTitleSize = 24
+	menuButtonW   = 180
+	keysCols      = 2 // the key bindings are in two columns
+	menuRowW      = 200
+)
+
+// Menu is the title screen, the pause menu and the options. The buttons are
+// ebitenui widgets. Keyboard and gamepad move the focus, the mouse focus the
+// button under the cursor
+type Menu struct {
+	ui      *ebitenui.UI
+	screen  menuScreen
+	back    menuScreen // screen the options go back to
+	items   []*menuItem
+	rows    []menuRow // text above the buttons
+	cols    int
+	focus   int
+	binding string // action waiting for a new key, "" when not binding
+	started bool   // a game is running, Continue go back to it
+	face    text.Face
+}
+
+// menuItem is a button. Left and right change options with adjust
+type menuItem struct {
+	button   *widget.Button
+	label    func() string
+	click    func()
+	adjust   func(dir int) // nil for buttons that only click
+	disabled bool
+}
+
+// menuRow is a line of text in column col
+type menuRow struct {
+	text string
+	c    color.Color
+	col  int
+}
+
+// the game start at the title screen
+func (g *Game) newMenu() {
+	g.menu = &Menu{
+		ui:   &ebitenui.UI{DisableDefaultFocus: true},
+		face: &text.GoTextFace{Source: mplusFaceSource, Size: menuTextSize},
+	}
+	g.openMenu(menuTitle)
+}
+
+// build the buttons of screen
+func (g *Game) openMenu(screen menuScreen) {
+	m := g.menu
+	if (screen == menuOptions || screen == menuAchievements) && (m.screen == menuTitle || m.screen == menuPause) {
+		m.back = m.screen
+	}
+	m.screen, m.focus, m.binding, m.cols = screen, 0, "", 1
+	m.items, m.rows = nil, nil
+	title := ""
+	switch screen {
+	case menuNone:
+		return
+	case menuTitle:
+		title = "Gopher Land"
+		m.add(func() string { return tr("New Game") }, g.newGameMenu)
+		m.add(func() string { return tr("Continue") }, g.resume).disabled = !m.started
+		m.add(func() string { return tr("Load") }, g.loadMenu).disabled = !saveExists()
+		m.add(func() string { return tr("Achievements") }, func() { g.openMenu(menuAchievements) })
+		m.add(func() string { return tr("Options") }, func() { g.openMenu(menuOptions) })
+		m.add(func() string { return tr("Quit") }, g.quitGame)
+	case menuPause:
+		title = tr("Pause")
+		m.add(func() string { return tr("Resume") }, g.resume)
+		m.add(func() string { return tr("Save") }, g.saveGame)
+		m.add(func() string { return tr("Load") }, g.loadMenu).disabled = !saveExists()
+		m.add(func() string { return tr("Achievements") }, func() { g.openMenu(menuAchievements) })
+		m.add(func() string { return tr("Options") }, func() { g.openMenu(menuOptions) })
+		m.add(func() string { return tr("Main menu") }, func() { g.openMenu(menuTitle) })
+		m.add(func() string { return tr("Quit") }, g.quitGame)
+	case menuOptions:
+		title = tr("Options")
+		m.addOption(func() string { return fmt.Sprintf("%s: %.0f%%", tr("Music"), options.Music*100) }, func(dir int) {
+			options.Music = stepVolume(options.Music, dir)
+		})
+		m.addOption(func() string { return fmt.Sprintf("%s: %.0f%%", tr("Sound"), options.Sound*100) }, func(dir int) {
+			options.Sound = stepVolume(options.Sound, dir)
+			playSound(audioCoin)
+		})
+		m.addOption(func() string { return tr("Fullscreen") + ": " + onOff(options.Fullscreen) }, func(int) {
+			options.Fullscreen = !options.Fullscreen
+		})
+		m.addOption(func() string { return fmt.Sprintf("%s: %dx", tr("Window scale"), options.Scale) }, func(dir int) {
+			options.Scale = (options.Scale+dir+maxScale-1)%maxScale + 1
+		})
+		m.addOption(func() string { return tr("Language") + ": " + tr("English") }, func(dir int) {
+			i := slices.Index(languages, options.Language)
+			options.Language = languages[(i+dir+len(languages))%len(languages)]
+			g.openMenu(menuOptions) // new texts
+			g.menu.focus = 4
+		})
+		m.add(func() string { return tr("Key bindings") }, func() { g.openMenu(menuKeys) })
+		m.add(func() string { return tr("Back") }, g.menuBack)
+	case menuKeys:
+		title = tr("Key bindings")
+		m.cols = keysCols
+		for _, a := range actions {
+			m.add(func() string {
+				if m.binding == a {
+					return tr(a) + ": " + tr("Press a key")
+				}
+				return tr(a) + ": " + keyOf(a).String()
+			}, func() { m.binding = a })
+		}
+		m.add(func() string { return tr("Back") }, g.menuBack)
+	case menuAchievements:
+		title = tr("Achievements")
+		g.achievementRows()
+		m.add(func() string { return tr("Back") }, g.menuBack)
+	}
+	m.build(title)
+	for m.items[m.focus].disabled {
+		m.focus++
+	}
+}
+
+// add button
+func (m *Menu) add(label func() string, click func()) *menuItem {
+	item := &menuItem{label: label, click: click}
+	m.items = append(m.items, item)
+	return item
+}
+
+// add a line of text to column col
+func (m *Menu) addRow(text string, c color.Color, col int) {
+	m.rows = append(m.rows, menuRow{text, c, col})
+}
+
+// add option that change with left, right and click. The options are used and saved at once
+func (m *Menu) addOption(label func() string, adjust func(dir int)) *menuItem {
+	item := m.add(label, nil)
+	item.adjust = func(dir int) {
+		adjust(dir)
+		applyOptions()
+		saveOptions()
+	}
+	item.click = func() { item.adjust(1) }
+	return item
+}
+
+// lay out the title and the buttons in the middle of the screen
+func (m *Menu) build(title string) {
+	root := widget.NewContainer(widget.ContainerOpts.Layout(widget.NewAnchorLayout()))
+	panel := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(eimage.NewNineSliceColor(blue_transp)),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+			widget.RowLayoutOpts.Padding(widget.NewInsetsSimple(10)),
+			widget.RowLayoutOpts.Spacing(4),
+		)),
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+			HorizontalPosition: widget.AnchorLayoutPositionCenter,
+			VerticalPosition:   widget.AnchorLayoutPositionCenter,
+		})),
+	)
+	panel.AddChild(widget.NewText(
+		widget.TextOpts.Text(title, &text.GoTextFace{Source: mplusFaceSource, Size: menuTitleSize}, yellow),
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{Position: widget.RowLayoutPositionCenter})),
+	))
+	if len(m.rows) > 0 {
+		cols := widget.NewContainer(widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Spacing(10),
+		)))
+		for _, r := range m.rows {
+			for len(cols.Children()) <= r.col {
+				cols.AddChild(widget.NewContainer(
+					widget.ContainerOpts.Layout(widget.NewRowLayout(
+						widget.RowLayoutOpts.Direction(widget.DirectionVertical),
+						widget.RowLayoutOpts.Spacing(2),
+					)),
+					widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.MinSize(menuRowW, 0)),
+				))
+			}
+			cols.Children()[r.col].(*widget.Container).AddChild(widget.NewText(widget.TextOpts.Text(r.text, m.face, r.c)))
+		}
+		panel.AddChild(cols)
+	}
+	grid := widget.NewContainer(widget.ContainerOpts.Layout(widget.NewGridLayout(
+		widget.GridLayoutOpts.Columns(m.cols),
+		widget.GridLayoutOpts.Spacing(4, 2),
+	)))
+	buttonImg := &widget.ButtonImage{
+		Idle:     eimage.NewNineSliceColor(blue_rect),
+		Hover:    eimage.NewNineSliceColor(orange),
+		Pressed:  eimage.NewNineSliceColor(brown),
+		Disabled: eimage.NewNineSliceColor(blue_transp),
+	}
+	for i, item := range m.items {
+		item.button = widget.NewButton(
+			widget.ButtonOpts.Image(buttonImg),
+			widget.ButtonOpts.Text(item.label(), m.face, &widget.ButtonTextColor{Idle: white, Hover: black, Disabled: gray}),
+			widget.ButtonOpts.TextPadding(widget.Insets{Top: 2, Bottom: 2}),
+			widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.MinSize(menuButtonW, 0)),
+			widget.ButtonOpts.DisableDefaultKeys(),
+			widget.ButtonOpts.ClickedHandler(func(*widget.ButtonClickedEventArgs) {
+				m.focus = i
+				item.click()
+			}),
+			widget.ButtonOpts.CursorEnteredHandler(func(*widget.ButtonHoverEventArgs) {
+				if !item.disabled {
+					m.focus = i
+				}
+			}),
+		)
+		item.button.GetWidget().Disabled = item.disabled
+		grid.AddChild(item.button)
+	}
+	panel.AddChild(grid)
+	root.AddChild(panel)
+	m.ui.Container = root
+}
+
+// move the focus by step, over disabled buttons
+func (m *Menu) move(step int) {
+	for i := m.focus + step; i >= 0 && i < len(m.items); i += step {
+		if !m.items[i].disabled {
+			m.focus = i
+			playSound(audioFx)
+			return
+		}
+	}
+}
+
+// menu keys. Arrows, the movement keys and the gamepad move the focus,
+// Enter, Space and gamepad A click, Escape and gamepad B go back
+func (g *Game) updateMenu() {
+	m := g.menu
+	if m.binding != "" {
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			m.binding = ""
+		} else if keys := inpututil.AppendJustPressedKeys(nil); len(keys) > 0 {
+			bindKey(m.binding, keys[0])
+			m.binding = ""
+			saveOptions()
+		}
+		m.refresh()
+		return
+	}
+	item := m.items[m.focus]
+	switch {
+	case menuKey(actUp, ebiten.KeyArrowUp, ebiten.StandardGamepadButtonLeftTop):
+		m.move(-m.cols)
+	case menuKey(actDown, ebiten.KeyArrowDown, ebiten.StandardGamepadButtonLeftBottom):
+		m.move(m.cols)
+	case menuKey(actLeft, ebiten.KeyArrowLeft, ebiten.StandardGamepadButtonLeftLeft):
+		if item.adjust != nil {
+			item.adjust(-1)
+		} else if m.cols > 1 {
+			m.move(-1)
+		}
+	case menuKey(actRight, ebiten.KeyArrowRight, ebiten.StandardGamepadButtonLeftRight):
+		if item.adjust != nil {
+			item.adjust(1)
+		} else if m.cols > 1 {
+			m.move(1)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) ||
+		gamepadJustPressed(ebiten.StandardGamepadButtonRightBottom):
+		item.button.Click()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || gamepadJustPressed(ebiten.StandardGamepadButtonRightRight):
+		g.menuBack()
+	}
+	m.ui.Update()
+	g.menu.refresh()
+}
+
+// movement key, arrow key or gamepad button was pressed
+func menuKey(action string, arrow ebiten.Key, pad ebiten.StandardGamepadButton) bool {
+	return keyJustPressed(action) || inpututil.IsKeyJustPressed(arrow) || gamepadJustPressed(pad)
+}
+
+// new labels and focus on the buttons
+func (m *Menu) refresh() {
+	for i, item := range m.items {
+		item.button.Text().Label = item.label()
+		item.button.Focus(i == m.focus)
+	}
+}
+
+// Escape go back one menu, or back to the game from the pause menu
+func (g *Game) menuBack() {
+	switch g.menu.screen {
+	case menuPause:
+		g.resume()
+	case menuOptions:
+		g.openMenu(g.menu.back)
+	case menuKeys:
+		g.openMenu(menuOptions)
+	case menuAchievements:
+		g.openMenu(g.menu.back)
+	}
+}
+
+// New Game start the village from the beginning
+func (g *Game) newGameMenu() {
+	if g.menu.started {
+		g.restart()
+	}
+	g.resume()
+}
+
+// Load the saved game and play
+func (g *Game) loadMenu() {
+	g.loadGame()
+	g.resume()
+}
+
+// close the menu and play
+func (g *Game) resume() {
+	g.menu.started = true
+	g.openMenu(menuNone)
+}
+
+// Escape key or gamepad start open the pause menu
+func (g *Game) pauseGame() {
+	g.openMenu(menuPause)
+}
+
+// a new game, the menu, music and options stay
+func (g *Game) restart() {
+	menu := g.menu
+	*g = *newGame()
+	g.menu = menu
+}
+
+// there is a saved game to load
+func saveExists() bool {
+	_, err := os.Stat(saveFile)
+	return err == nil
+}
+
+// volume up or down in steps of 10%
+func stepVolume(v float64, dir int) float64 {
+	return min(max(v+0.1*float64(dir), 0), 1)
+}
+
+func onOff(on bool) string {
+	if on {
+		return tr("On")
+	}
+	return tr("Off")
+}
+
+// draw the open menu over the game
+func (g *Game) drawMenu(screen *ebiten.Image) {
+	if g.menu.screen == menuNone {
+		return
+	}
+	vector.DrawFilledRect(screen, 0, 0, screenWidth, screenHeight, blue_transp, true)
+	g.menu.ui.Draw(screen)
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	optionsFile = saveDir + "/options.json"
+	musicVolume = 0.2 // music and sounds at full volume in the options
+	soundVolume = 0.3
+	maxScale    = 3
+)
+
+// actions that can be bound to a key, in the order of the key bindings menu
+const (
+	actUp         = "up"
+	actDown       = "down"
+	actLeft       = "left"
+	actRight      = "right"
+	actAction     = "action"
+	actInteract   = "interact"
+	actTill       = "till"
+	actPlant      = "plant"
+	actWater      = "water"
+	actBag        = "bag"
+	actWorkers    = "workers"
+	actBuild      = "build"
+	actNext       = "next"
+	actCalendar   = "calendar"
+	actQuests     = "quests"
+	actSell       = "sell"
+	actBuy        = "buy"
+	actPause      = "pause"
+	actFullscreen = "fullscreen"
+	actQuit       = "quit"
+	actSave       = "save"
+	actLoad       = "load"
+)
+
+var actions = []string{
+	actUp, actDown, actLeft, actRight, actAction, actInteract, actTill, actPlant, actWater, actBag,
+	actWorkers, actBuild, actNext, actCalendar, actQuests, actSell, actBuy, actPause, actFullscreen, actQuit, actSave, actLoad,
+}
+
+// Options are saved in their own file, not with the game
+type Options struct {
+	Music      float64               `json:"music"` // 0-1
+	Sound      float64               `json:"sound"`
+	Fullscreen bool                  `json:"fullscreen"`
+	Scale      int                   `json:"scale"` // window size in screen sizes
+	Language   string                `json:"language"`
+	Keys       map[string]ebiten.Key `json:"keys"` // action to key
+}
+
+var options = defaultOptions()
+
+// background music, the volume follow the options
+var musicPlayer *audio.Player
+
+func defaultOptions() Options {
+	return Options{
+		Music:    1,
+		Sound:    1,
+		Scale:    2,
+		Language: "en",
+		Keys: map[string]ebiten.Key{
+			actUp:         ebiten.KeyK,
+			actDown:       ebiten.KeyJ,
+			actLeft:       ebiten.KeyH,
+			actRight:      ebiten.KeyL,
+			actAction:     ebiten.KeyA,
+			actInteract:   ebiten.KeyE,
+			actTill:       ebiten.KeyT,
+			actPlant:      ebiten.KeyP,
+			actWater:      ebiten.KeyW,
+			actBag:        ebiten.KeyI,
+			actWorkers:    ebiten.KeyTab,
+			actBuild:      ebiten.KeyB,
+			actNext:       ebiten.KeyN,
+			actCalendar:   ebiten.KeyC,
+			actQuests:     ebiten.KeyO,
+			actSell:       ebiten.KeyS,
+			actBuy:        ebiten.KeyB, // the market is open alone, buy and build can share a key
+			actPause:      ebiten.KeyEscape,
+			actFullscreen: ebiten.KeyF,
+			actQuit:       ebiten.KeyQ,
+			actSave:       ebiten.KeyF5,
+			actLoad:       ebiten.KeyF9,
+		},
+	}
+}
+
+// load options.json, missing options keep the default
+func loadOptions() {
+	content, err := os.ReadFile(optionsFile)
+	if err != nil {
+		return // first start
+	}
+	o := defaultOptions()
+	if err := json.Unmarshal(content, &o); err != nil {
+		log.Println("options:", err)
+		return
+	}
+	for _, a := range actions {
+		if _, ok := o.Keys[a]; !ok {
+			o.Keys[a] = defaultOptions().Keys[a]
+		}
+	}
+	o.Scale = min(max(o.Scale, 1), maxScale)
+	options = o
+}
+
+// save options.json
+func saveOptions() {
+	content, err := json.MarshalIndent(options, "", "  ")
+	if err != nil {
+		log.Println("options:", err)
+		return
+	}
+	if err := os.MkdirAll(saveDir, 0755); err != nil {
+		log.Println("options:", err)
+		return
+	}
+	if err := os.WriteFile(optionsFile, content, 0644); err != nil {
+		log.Println("options:", err)
+	}
+}
+
+// set volume, fullscreen and window size from the options
+func applyOptions() {
+	if musicPlayer != nil {
+		musicPlayer.SetVolume(musicVolume * options.Music)
+	}
+	ebiten.SetFullscreen(options.Fullscreen)
+	ebiten.SetWindowSize(screenWidth*options.Scale, screenHeight*options.Scale)
+}
+
+// key bound to action
+func keyOf(action string) ebiten.Key {
+	return options.Keys[action]
+}
+
+// key for action is held down
+func keyPressed(action string) bool {
+	return ebiten.IsKeyPressed(keyOf(action))
+}
+
+// key for action was pressed this tick
+func keyJustPressed(action string) bool {
+	return inpututil.IsKeyJustPressed(keyOf(action))
+}
+
+// bind key to action. The action that had the key get the old key of action
+func bindKey(action string, key ebiten.Key) {
+	old := options.Keys[action]
+	for a, k := range options.Keys {
+		if k == key {
+			options.Keys[a] = old
+		}
+	}
+	options.Keys[action] = key
+}
+
+// button was pressed this tick on any gamepad
+func gamepadJustPressed(b ebiten.StandardGamepadButton) bool {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if inpututil.IsStandardGamepadButtonJustPressed(id, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// languages in the options, the first is the default
+var languages = []string{"en", "sv"}
+
+// menu texts in other languages than English
+var translations = map[string]map[string]string{
+	"sv": {
+		"English":                "Svenska",
+		"New Game":               "Nytt spel",
+		"Continue":               "Fortsätt",
+		"Load":                   "Ladda",
+		"Save":                   "Spara",
+		"Options":                "Inställningar",
+		"Quit":                   "Avsluta",
+		"Pause":                  "Paus",
+		"Resume":                 "Fortsätt spela",
+		"Main menu":              "Huvudmeny",
+		"Music":                  "Musik",
+		"Sound":                  "Ljud",
+		"Fullscreen":             "Helskärm",
+		"Window scale":           "Fönsterstorlek",
+		"Language":               "Språk",
+		"Key bindings":           "Tangenter",
+		"Back":                   "Tillbaka",
+		"Achievements":           "Prestationer",
+		"Statistics":             "Statistik",
+		"Crops harvested":        "Skördade grödor",
+		"Coins earned":           "Intjänade mynt",
+		"Chickens delivered":     "Levererade hönor",
+		"Distance walked":        "Gången sträcka",
+		"tiles":                  "rutor",
+		"Play time":              "Speltid",
+		"Achievement unlocked":   "Prestation upplåst",
+		"New quest":              "Nytt uppdrag",
+		"Quest done":             "Uppdrag klart",
+		"Village":                "Byn",
+		"Basket full":            "Korgen är full",
+		"Wallet full":            "Plånboken är full",
+		"Bag full":               "Väskan är full",
+		"Carrying a chicken":     "Du bär redan en höna",
+		"Chicken house full":     "Hönshuset är fullt",
+		"No house for a worker":  "Inget hus för arbetaren",
+		"Not enough coins":       "För lite mynt",
+		"Not enough items":       "För få saker",
+		"No room for the reward": "Ingen plats för belöningen",
+		"On":                     "På",
+		"Off":                    "Av",
+		"Press a key":            "Tryck en tangent",
+		"up":                     "upp",
+		"down":                   "ner",
+		"left":                   "vänster",
+		"right":                  "höger",
+		"action":                 "info",
+		"interact":               "använd",
+		"till":                   "plöj",
+		"plant":                  "plantera",
+		"water":                  "vattna",
+		"bag":                    "väska",
+		"workers":                "arbetare",
+		"build":                  "bygg",
+		"next":                   "nästa ritning",
+		"calendar":               "kalender",
+		"quests":                 "uppdrag",
+		"sell":                   "sälj",
+		"buy":                    "köp",
+		"pause":                  "paus",
+		"fullscreen":             "helskärm",
+		"quit":                   "avsluta",
+		"save":                   "spara",
+		"load":                   "ladda",
+	},
+}
+
+// tr translate a menu text to the language in the options
+func tr(s string) string {
+	if tr, ok := translations[options.Language][s]; ok {
+		return tr
+	}
+	return s
+}
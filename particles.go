@@ -0,0 +1,204 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	maxParticles = 512 // particles in the pool
+	smokeFrames  = 6   // frames in smoke.png
+	smokeSize    = 32
+	puffTicks    = 8 // at most one smoke puff every 8 ticks
+)
+
+// ParticleStyle is how particles move and look. Particles with an image
+// play the frames over their lifetime, without an image they are dots
+type ParticleStyle struct {
+	img     *ebiten.Image
+	frames  []image.Rectangle
+	c       color.RGBA // dot color
+	size    float32    // dot radius
+	life    int        // ticks a particle live
+	speed   float64    // random start speed in any direction
+	vel     Point      // start velocity
+	gravity float64
+	fade    bool // fade out over the lifetime
+}
+
+type particle struct {
+	pos, vel Point
+	age      int
+	style    *ParticleStyle
+}
+
+// Particles is a fixed pool. Dead particles are used again, the oldest
+// particle is replaced when the pool is full
+type Particles struct {
+	pool  [maxParticles]particle
+	alive int // particles in use are pool[:alive]
+}
+
+// Emitter spawn particles at pos, rate particles every tick
+type Emitter struct {
+	style  *ParticleStyle
+	pos    Point
+	rate   float64
+	acc    float64
+	active bool
+}
+
+// spawn one particle at pos
+func (p *Particles) Spawn(style *ParticleStyle, pos Point) {
+	i := p.alive
+	if i == maxParticles {
+		i = p.oldest()
+	} else {
+		p.alive++
+	}
+	a := rand.Float64() * 2 * math.Pi
+	s := rand.Float64() * style.speed
+	p.pool[i] = particle{
+		pos:   pos,
+		vel:   Point{style.vel.x + math.Cos(a)*s, style.vel.y + math.Sin(a)*s},
+		style: style,
+	}
+}
+
+// spawn n particles at pos
+func (p *Particles) Burst(style *ParticleStyle, pos Point, n int) {
+	for range n {
+		p.Spawn(style, pos)
+	}
+}
+
+// remove all particles
+func (p *Particles) Clear() {
+	p.alive = 0
+}
+
+func (p *Particles) oldest() int {
+	old := 0
+	for i := range p.pool {
+		if p.pool[i].age > p.pool[old].age {
+			old = i
+		}
+	}
+	return old
+}
+
+// move particles and remove dead particles
+func (p *Particles) Update() {
+	for i := 0; i < p.alive; {
+		pt := &p.pool[i]
+		pt.age++
+		if pt.age >= pt.style.life {
+			p.alive--
+			p.pool[i] = p.pool[p.alive] // swap the last particle in
+			continue
+		}
+		pt.vel.y += pt.style.gravity
+		pt.pos.x += pt.vel.x
+		pt.pos.y += pt.vel.y
+		i++
+	}
+}
+
+// draw all particles
+func (p *Particles) Draw(screen *ebiten.Image) {
+	for i := range p.alive {
+		pt := &p.pool[i]
+		alpha := float32(1)
+		if pt.style.fade {
+			alpha = 1 - float32(pt.age)/float32(pt.style.life)
+		}
+		if pt.style.img == nil {
+			c := pt.style.c
+			c.R, c.G, c.B, c.A = byte(float32(c.R)*alpha), byte(float32(c.G)*alpha), byte(float32(c.B)*alpha), byte(float32(c.A)*alpha)
+			vector.DrawFilledCircle(screen, float32(pt.pos.x), float32(pt.pos.y), pt.style.size, c, true)
+			continue
+		}
+		frame := pt.style.frames[pt.age*len(pt.style.frames)/pt.style.life]
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(pt.pos.x-float64(frame.Dx())/2, pt.pos.y-float64(frame.Dy())/2)
+		op.ColorScale.ScaleAlpha(alpha)
+		screen.DrawImage(subImage(pt.style.img, frame), op)
+	}
+}
+
+// spawn particles for this tick
+func (e *Emitter) Emit(p *Particles) {
+	if !e.active {
+		e.acc = 0
+		return
+	}
+	e.acc += e.rate
+	for ; e.acc >= 1; e.acc-- {
+		p.Spawn(e.style, e.pos)
+	}
+}
+
+// particle styles for smoke puffs, coin sparkles, walking dust and fire embers
+func (g *Game) initParticles(smokeImg *ebiten.Image) {
+	g.particles = &Particles{}
+	g.smokeFx = &ParticleStyle{img: smokeImg, life: 36, vel: Point{0, -0.2}, fade: true}
+	for i := range smokeFrames {
+		g.smokeFx.frames = append(g.smokeFx.frames, image.Rect(i*smokeSize, 0, (i+1)*smokeSize, smokeSize))
+	}
+	g.sparkleFx = &ParticleStyle{c: color.RGBA{255, 230, 80, 255}, size: 1.2, life: 30, speed: 1.2, gravity: 0.03, fade: true}
+	g.dustFx = &ParticleStyle{c: color.RGBA{180, 150, 110, 200}, size: 1.5, life: 20, speed: 0.3, vel: Point{0, -0.2}, fade: true}
+	g.emberFx = &ParticleStyle{c: color.RGBA{255, 160, 60, 255}, size: 1, life: 40, speed: 0.2, vel: Point{0, -0.6}, fade: true}
+
+	g.dust = &Emitter{style: g.dustFx, rate: 0.25}
+	g.emitters = append(g.emitters, g.dust)
+	for _, f := range g.fires {
+		f.embers = &Emitter{style: g.emberFx, pos: Point{f.pos.x + fireFrame/2, f.pos.y}, rate: 0.1, active: f.scene == 0}
+		g.emitters = append(g.emitters, f.embers)
+	}
+}
+
+// smoke puff at pos. Collisions call puff every tick, so puffs are spaced out
+func (g *Game) puff(pos Point) {
+	if g.clock.ticks-g.lastPuff < puffTicks {
+		return
+	}
+	g.lastPuff = g.clock.ticks
+	g.particles.Spawn(g.smokeFx, pos)
+}
+
+// coin sparkles at pos
+func (g *Game) sparkle(pos Point) {
+	g.particles.Burst(g.sparkleFx, pos, 8)
+}
+
+// smoke puffs and sparkles for the game events
+func subscribeParticles(b *EventBus) {
+	Subscribe(b, func(g *Game, e ItemPicked) { g.puff(e.Pos) })
+	Subscribe(b, func(g *Game, e CoinEarned) { g.sparkle(e.Pos) })
+	Subscribe(b, func(g *Game, e ItemDelivered) {
+		if e.Item == coinItem {
+			g.sparkle(Point{g.Player.pos.x + imgSize/2, g.Player.pos.y + imgSize/4})
+		}
+	})
+	Subscribe(b, func(g *Game, e WorkerHired) { g.sparkle(Point{e.Worker.pos.x + imgSize/2, e.Worker.pos.y + imgSize/4}) })
+	Subscribe(b, func(g *Game, e WorkerPaid) { g.sparkle(Point{e.Worker.pos.x + imgSize/2, e.Worker.pos.y + imgSize/4}) })
+	Subscribe(b, func(g *Game, e QuestCompleted) {
+		g.sparkle(Point{g.Player.pos.x + imgSize/2, g.Player.pos.y + imgSize/4})
+	})
+	Subscribe(b, func(g *Game, e SceneChanged) { g.particles.Clear() }) // particles stay in their scene
+}
+
+// dust at the Player feet while walking, then move all particles
+func (g *Game) updateParticles() {
+	g.dust.pos = Point{g.Player.pos.x + imgSize/2, g.Player.pos.y + imgSize*3/4}
+	g.dust.active = g.Player.pos != g.Player.prePos
+	for _, e := range g.emitters {
+		e.Emit(g.particles)
+	}
+	g.particles.Update()
+}
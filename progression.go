@@ -0,0 +1,87 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/eklownr/gorpg/tiers"
+)
+
+// game stats the tier and dialogue rules can use. Dialogue flags are "flag.<name>"
+func (g *Game) stats() map[string]int {
+	stats := map[string]int{
+		"visits":   g.buddaVisits,
+		"sales":    g.sales,
+		"coins":    g.Player.inv.Count(coinItem),
+		"harvests": g.harvests,
+		"chickens": g.chickensDelivered,
+		"workers":  g.hiredWorkers(),
+		"day":      g.clock.Day(),
+		"tier":     g.tier,
+	}
+	for flag := range g.flags {
+		stats["flag."+flag] = 1
+	}
+	return stats
+}
+
+// count sales, harvests and delivered chickens for the stats
+func subscribeStats(b *EventBus) {
+	Subscribe(b, func(g *Game, e ItemSold) { g.sales++ })
+	Subscribe(b, func(g *Game, e ChickenDelivered) { g.chickensDelivered++ })
+	Subscribe(b, func(g *Game, e ItemPicked) {
+		if _, ok := g.crops.Get(e.Item); ok {
+			g.harvests++
+		}
+	})
+}
+
+// workers with a contract
+func (g *Game) hiredWorkers() int {
+	n := 0
+	for _, w := range g.workers {
+		if w.contract != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// reach the next tiers when the rules pass
+func (g *Game) updateProgress() {
+	stats := g.stats()
+	for g.tier+1 < len(g.tiers.Tiers) && g.tiers.Tiers[g.tier+1].Reached(stats) {
+		g.tier++
+		g.unlockTier(g.tiers.Tiers[g.tier].Unlock)
+		Publish(g, TierReached{g.tier})
+	}
+}
+
+// unlock workers, scene and buildings
+func (g *Game) unlockTier(u tiers.Unlock) {
+	for _, i := range u.Workers {
+		if i >= 0 && i < len(g.workers) {
+			g.workers[i].active = true
+		}
+	}
+	if u.Scene != nil && g.inside != nil {
+		g.outsideScene = *u.Scene // go to the new scene when the Player leave the house
+	} else if u.Scene != nil {
+		g.setScene(*u.Scene)
+	}
+	for _, house := range g.house {
+		if slices.Contains(u.Buildings.Hide, house.variety) {
+			house.active = false
+		}
+		if slices.Contains(u.Buildings.Show, house.variety) {
+			house.active = true
+		}
+	}
+}
+
+// progress 0-1 toward the next tier
+func (g *Game) tierProgress() float64 {
+	if g.tier+1 >= len(g.tiers.Tiers) {
+		return 1
+	}
+	return g.tiers.Tiers[g.tier+1].Progress(g.stats())
+}
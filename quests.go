@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/eklownr/gorpg/dialogues"
+	"github.com/eklownr/gorpg/quests"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	questLogX   = 120
+	questLogY   = 40
+	questLogW   = 400
+	questLogRow = 12
+)
+
+// Quest is a started quest and the count of every objective
+type Quest struct {
+	*quests.Quest
+	progress []int
+	done     bool
+}
+
+// start quest by name. Quests are only started once
+func (g *Game) startQuest(name string) {
+	data, ok := g.questsData.Get(name)
+	if !ok {
+		log.Println("quests: unknown quest", name)
+		return
+	}
+	for _, q := range g.quests {
+		if q.Name == name {
+			return
+		}
+	}
+	q := &Quest{Quest: data, progress: make([]int, len(data.Objectives))}
+	g.quests = append(g.quests, q)
+	Publish(g, QuestStarted{q})
+}
+
+// every quest a dialogue start must be in quests.json
+func checkDialogueQuests(d *dialogues.DialoguesJSON, q *quests.QuestsJSON) error {
+	for _, dialogue := range d.Dialogues {
+		for _, n := range dialogue.Nodes {
+			for _, e := range n.Effects {
+				if _, ok := q.Get(e.Quest); e.Quest != "" && !ok {
+					return fmt.Errorf("dialogues: %s: unknown quest %s", dialogue.Name, e.Quest)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// objectives count picked, sold and delivered items and finished buildings
+func subscribeQuests(b *EventBus) {
+	Subscribe(b, func(g *Game, e ItemPicked) { g.questEvent(quests.Collect, e.Item, e.Count) })
+	Subscribe(b, func(g *Game, e ItemSold) { g.questEvent(quests.Sell, e.Item, 1) })
+	Subscribe(b, func(g *Game, e ItemDelivered) { g.questEvent(quests.Deliver, e.Item, 1) })
+	Subscribe(b, func(g *Game, e ChickenDelivered) { g.questEvent(quests.Deliver, chickenItem, 1) })
+	Subscribe(b, func(g *Game, e BuildingFinished) { g.questEvent(quests.Build, e.Building.blueprint.Name, 1) })
+}
+
+// quests that begin with the game, without a sound
+func (g *Game) startQuests() {
+	for _, q := range g.questsData.Quests {
+		if q.Start {
+			g.quests = append(g.quests, &Quest{Quest: q, progress: make([]int, len(q.Objectives))})
+		}
+	}
+}
+
+// count n events for the objectives of the active quests
+func (g *Game) questEvent(event, item string, n int) {
+	for _, q := range g.quests {
+		if q.done {
+			continue
+		}
+		for i, o := range q.Objectives {
+			if o.Match(event, item) {
+				q.progress[i] = min(q.progress[i]+n, o.Count)
+			}
+		}
+		if q.complete() {
+			g.completeQuest(q)
+		}
+	}
+}
+
+// finish quests that wait for room in the bag for their rewards
+func (g *Game) updateQuests() {
+	for _, q := range g.quests {
+		if !q.done && q.complete() {
+			g.completeQuest(q)
+		}
+	}
+}
+
+// all objectives are done
+func (q *Quest) complete() bool {
+	for i, o := range q.Objectives {
+		if q.progress[i] < o.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// give the rewards and start the next quest in the chain. The quest wait
+// until all rewards fit in the bag
+func (g *Game) completeQuest(q *Quest) {
+	var rewards []ItemStack
+	for _, r := range q.Rewards {
+		rewards = append(rewards, ItemStack{r.Item, max(r.Count, 1)})
+	}
+	if !g.Player.inv.Fits(rewards...) {
+		Publish(g, ActionFailed{"No room for the reward", rewards[0].Item})
+		return
+	}
+	q.done = true
+	for _, r := range rewards {
+		g.Player.inv.Add(r.Item, r.Count)
+	}
+	Publish(g, QuestCompleted{q})
+	if q.Next != "" {
+		g.startQuest(q.Next)
+	}
+}
+
+// hint for the first objective that is not done in the first active quest
+func (g *Game) questHint() string {
+	for _, q := range g.quests {
+		if q.done {
+			continue
+		}
+		for i, o := range q.Objectives {
+			if q.progress[i] < o.Count {
+				return fmt.Sprintf("%s %d/%d", o.Hint, q.progress[i], o.Count)
+			}
+		}
+	}
+	return ""
+}
+
+// O key show or hide the quest log
+func (g *Game) questLogKey() {
+	g.questLogOpen = !g.questLogOpen
+}
+
+// draw active quests with their objectives, and the done quests
+func (g *Game) drawQuestLog(screen *ebiten.Image) {
+	if !g.questLogOpen {
+		return
+	}
+	rows := 1
+	for _, q := range g.quests {
+		rows++
+		if !q.done {
+			rows += 1 + len(q.Objectives)
+		}
+	}
+	vector.DrawFilledRect(screen, questLogX, questLogY, questLogW, float32(rows*questLogRow+12), blue_transp, true)
+	addTextAt(screen, 10, "Quests", yellow, questLogX+8, questLogY+4)
+	y := float64(questLogY + 4 + questLogRow)
+	for _, q := range g.quests {
+		if q.done {
+			addTextAt(screen, 10, q.Name+"  done", gray, questLogX+8, y)
+			y += questLogRow
+			continue
+		}
+		addTextAt(screen, 10, q.Name, white, questLogX+8, y)
+		addTextAt(screen, 8, q.Description, orange, questLogX+16, y+questLogRow)
+		y += 2 * questLogRow
+		for i, o := range q.Objectives {
+			col := white
+			if q.progress[i] >= o.Count {
+				col = green
+			}
+			addTextAt(screen, 8, fmt.Sprintf("%s %d/%d", o.Hint, q.progress[i], o.Count), col, questLogX+24, y)
+			y += questLogRow
+		}
+	}
+}
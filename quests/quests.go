@@ -0,0 +1,92 @@
+package quests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// events the objectives count
+const (
+	Collect = "collect" // item picked up or harvested
+	Deliver = "deliver" // item delivered to the chicken house or a construction site
+	Sell    = "sell"    // item sold to the budda
+	Build   = "build"   // building finished
+)
+
+var events = []string{Collect, Deliver, Sell, Build}
+
+// Objective count Count events of Event with Item. An empty Item match every item
+type Objective struct {
+	Event string `json:"event"`
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+	Hint  string `json:"hint"` // shown on screen while the objective is not done
+}
+
+// Reward the Player get when all objectives are done
+type Reward struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+// Quest is a list of objectives. Quests with Start begin with the game, the
+// others are started by a dialogue or by the quest before them in the chain
+type Quest struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Start       bool        `json:"start"`
+	Objectives  []Objective `json:"objectives"`
+	Rewards     []Reward    `json:"rewards"`
+	Next        string      `json:"next"` // quest that start when this quest is done
+}
+
+type QuestsJSON struct {
+	Quests []*Quest `json:"quests"`
+	byName map[string]*Quest
+}
+
+func NewQuestsJSON(filepath string) (*QuestsJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var questsJSON QuestsJSON
+	err = json.Unmarshal(content, &questsJSON)
+	if err != nil {
+		return nil, err
+	}
+	questsJSON.byName = make(map[string]*Quest)
+	for _, q := range questsJSON.Quests {
+		if questsJSON.byName[q.Name] != nil {
+			return nil, fmt.Errorf("quests: %s is defined twice", q.Name)
+		}
+		if len(q.Objectives) == 0 {
+			return nil, fmt.Errorf("quests: %s has no objectives", q.Name)
+		}
+		for _, o := range q.Objectives {
+			if !slices.Contains(events, o.Event) || o.Count <= 0 {
+				return nil, fmt.Errorf("quests: %s: bad objective %s %s %d", q.Name, o.Event, o.Item, o.Count)
+			}
+		}
+		questsJSON.byName[q.Name] = q
+	}
+	for _, q := range questsJSON.Quests {
+		if q.Next != "" && questsJSON.byName[q.Next] == nil {
+			return nil, fmt.Errorf("quests: %s: unknown next quest %q", q.Name, q.Next)
+		}
+	}
+	return &questsJSON, nil
+}
+
+// Get quest by name
+func (q *QuestsJSON) Get(name string) (*Quest, bool) {
+	quest, ok := q.byName[name]
+	return quest, ok
+}
+
+// Match when the event count for the objective
+func (o Objective) Match(event, item string) bool {
+	return o.Event == event && (o.Item == "" || o.Item == item)
+}
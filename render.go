@@ -0,0 +1,67 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Layer is drawn in order, ground first and UI last
+type Layer int
+
+const (
+	LayerGround   Layer = iota // tiles and farmland
+	LayerWorld                 // sprites sorted by the foot Y, lower on the screen is in front
+	LayerOverhead              // roofs, smoke and weather over the world
+	LayerUI                    // panels and text, not darkened by the night
+)
+
+// drawCmd draw one sprite or group of sprites
+type drawCmd struct {
+	layer Layer
+	y     float64 // foot Y, only used in LayerWorld
+	order int     // commands with the same layer and Y are drawn in the order they were added
+	draw  func(screen *ebiten.Image)
+}
+
+// RenderQueue collect the draws of a frame and draw them sorted by layer and Y
+type RenderQueue struct {
+	cmds []drawCmd
+	next int // order of the next command
+}
+
+// Add draw to layer. y is the foot of the sprite
+func (q *RenderQueue) Add(layer Layer, y float64, draw func(screen *ebiten.Image)) {
+	q.cmds = append(q.cmds, drawCmd{layer: layer, y: y, order: q.next, draw: draw})
+	q.next++
+}
+
+// Flush draw all commands up to and including layer last. Later layers stay in the queue
+func (q *RenderQueue) Flush(screen *ebiten.Image, last Layer) {
+	slices.SortFunc(q.cmds, func(a, b drawCmd) int {
+		if a.layer != b.layer {
+			return cmp.Compare(a.layer, b.layer)
+		}
+		if a.layer == LayerWorld && a.y != b.y {
+			return cmp.Compare(a.y, b.y)
+		}
+		return cmp.Compare(a.order, b.order)
+	})
+	n := 0
+	for _, c := range q.cmds {
+		if c.layer <= last {
+			c.draw(screen)
+			n++
+		}
+	}
+	q.cmds = slices.Delete(q.cmds, 0, n)
+	if len(q.cmds) == 0 {
+		q.next = 0
+	}
+}
+
+// foot Y of a Player or worker, the sprite has empty space below the feet
+func footY(c *Characters) float64 {
+	return c.pos.y + imgSize*3/4
+}
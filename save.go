@@ -0,0 +1,398 @@
+package main
+
+import (
+	"encoding/json"
+	"image"
+	"log"
+	"os"
+
+	"github.com/eklownr/gorpg/tilemaps"
+)
+
+const (
+	saveDir  = "save"
+	saveFile = saveDir + "/gorpg.json"
+	farmFile = saveDir + "/farm_bg.json" // farm tilemap with the farmland layer
+)
+
+// SaveGame is everything that is saved to disk, except the tilemaps
+type SaveGame struct {
+	Ticks     int                    `json:"ticks"` // clock ticks since day 0 at midnight
+	Inventory []ItemStack            `json:"inventory"`
+	Tilled    map[int]int            `json:"tilled"`
+	Plants    []SavePlant            `json:"plants"`
+	Chests    []SaveChest            `json:"chests"`
+	Progress  SaveProgress           `json:"progress"`
+	Buildings []SaveBuilding         `json:"buildings"`
+	Chickens  []SaveChicken          `json:"chickens"`
+	Eggs      []SaveChicken          `json:"eggs"` // eggs on the ground
+	CoopFeed  int                    `json:"coopFeed"`
+	Weather   SaveWeather            `json:"weather"`
+	Interiors map[string][]ItemStack `json:"interiors,omitempty"` // house storage by interior name
+	Quests    []SaveQuest            `json:"quests,omitempty"`
+	Flags     []string               `json:"flags,omitempty"` // dialogue flags
+	Player    *SavePlayer            `json:"player,omitempty"`
+	Workers   []SaveWorker           `json:"workers,omitempty"`
+	Market    *SaveMarket            `json:"market,omitempty"`
+}
+
+// SavePlayer is where the Player is and how much the Player can carry.
+// Inside a house it's the place outside the door
+type SavePlayer struct {
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Scene      int     `json:"scene"`
+	Wallet     int     `json:"wallet"`
+	BasketSize int     `json:"basketSize"`
+}
+
+// SaveWorker is a worker contract, the morale and the worker field
+type SaveWorker struct {
+	Morale   int           `json:"morale"`
+	Contract *SaveContract `json:"contract,omitempty"`
+	Field    SaveField     `json:"field"`
+}
+
+// SaveContract is a worker contract with the wages
+type SaveContract struct {
+	Wage    int `json:"wage"`
+	Cycles  int `json:"cycles"`
+	Done    int `json:"done"`
+	Unpaid  int `json:"unpaid"`
+	Overdue int `json:"overdue"`
+}
+
+// SaveField is the plant on a worker field
+type SaveField struct {
+	GrowTicks int  `json:"growTicks"`
+	Active    bool `json:"active"`
+	Picked    bool `json:"picked"`
+}
+
+// SaveMarket is the market prices by item and the transaction log
+type SaveMarket struct {
+	Prices map[string]float64 `json:"prices"`
+	Log    []SaveTransaction  `json:"log"`
+}
+
+// SaveTransaction is a sale or a purchase in the market log
+type SaveTransaction struct {
+	Day   int    `json:"day"`
+	Item  string `json:"item"`
+	Price int    `json:"price"`
+	Buy   bool   `json:"buy"`
+}
+
+// SaveQuest is a started quest and the count of every objective
+type SaveQuest struct {
+	Name     string `json:"name"`
+	Progress []int  `json:"progress"`
+	Done     bool   `json:"done"`
+}
+
+// SaveWeather is the weather state, the random seed and the numbers drawn from it
+type SaveWeather struct {
+	State string  `json:"state"`
+	Wind  float64 `json:"wind"`
+	Seed  int64   `json:"seed"`
+	Draws int64   `json:"draws"`
+}
+
+// SaveChicken is a chicken or an egg on the ground
+type SaveChicken struct {
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Active   bool    `json:"active"`
+	Pickable bool    `json:"pickable"`
+	Picked   bool    `json:"picked"`
+	Hunger   int     `json:"hunger"`
+	Age      int     `json:"age"`
+	LayTicks int     `json:"layTicks"`
+}
+
+// SaveBuilding is a construction site or a finished building
+type SaveBuilding struct {
+	Blueprint string         `json:"blueprint"`
+	X         float64        `json:"x"`
+	Y         float64        `json:"y"`
+	Delivered map[string]int `json:"delivered"`
+	Work      int            `json:"work"`
+	Storage   []ItemStack    `json:"storage,omitempty"`
+}
+
+// SaveProgress is the village tier and the stats the tier rules use
+type SaveProgress struct {
+	Tier              int `json:"tier"`
+	Sales             int `json:"sales"`
+	BuddaVisits       int `json:"buddaVisits"`
+	Harvests          int `json:"harvests"`
+	ChickensDelivered int `json:"chickensDelivered"`
+}
+
+// SaveChest is a storage chest the Player has placed
+type SaveChest struct {
+	X         float64     `json:"x"`
+	Y         float64     `json:"y"`
+	Scene     int         `json:"scene"`
+	Inventory []ItemStack `json:"inventory"`
+}
+
+// SavePlant is a plant the Player has planted
+type SavePlant struct {
+	Variety   string  `json:"variety"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	GrowTicks int     `json:"growTicks"`
+}
+
+// F5 key save the game
+func (g *Game) saveGame() {
+	save := SaveGame{
+		Ticks:     g.clock.ticks,
+		Inventory: g.Player.inv.Stacks(),
+		Tilled:    g.tilled,
+		CoopFeed:  g.coopFeed,
+		Weather:   SaveWeather{g.weather.state, g.weather.wind, g.weather.seed, g.weather.src.draws},
+		Progress:  SaveProgress{g.tier, g.sales, g.buddaVisits, g.harvests, g.chickensDelivered},
+	}
+	save.Player = &SavePlayer{g.Player.pos.x, g.Player.pos.y, g.scene, g.Player.wallet, g.Player.basketSize}
+	if g.inside != nil {
+		save.Player.X, save.Player.Y, save.Player.Scene = g.outsidePos.x, g.outsidePos.y, g.outsideScene
+	}
+	for i, w := range g.workers {
+		field := g.plants[i]
+		sw := SaveWorker{Morale: w.morale, Field: SaveField{field.growTicks, field.active, field.picked}}
+		if c := w.contract; c != nil {
+			sw.Contract = &SaveContract{c.wage, c.cycles, c.done, c.unpaid, c.overdue}
+		}
+		save.Workers = append(save.Workers, sw)
+	}
+	save.Market = &SaveMarket{Prices: make(map[string]float64)}
+	for _, it := range g.market.items {
+		save.Market.Prices[it.item] = it.price
+	}
+	for _, t := range g.market.log {
+		save.Market.Log = append(save.Market.Log, SaveTransaction{t.day, t.item, t.price, t.buy})
+	}
+	for _, q := range g.quests {
+		save.Quests = append(save.Quests, SaveQuest{q.Name, q.progress, q.done})
+	}
+	for flag := range g.flags {
+		save.Flags = append(save.Flags, flag)
+	}
+	for _, plant := range g.plants {
+		if plant.worker == nil {
+			save.Plants = append(save.Plants, SavePlant{plant.variety, plant.pos.x, plant.pos.y, plant.growTicks})
+		}
+	}
+	for _, c := range g.chests {
+		save.Chests = append(save.Chests, SaveChest{c.pos.x, c.pos.y, c.scene, c.inv.Stacks()})
+	}
+	for _, c := range g.chickens {
+		save.Chickens = append(save.Chickens, SaveChicken{c.pos.x, c.pos.y, c.active, c.pickable, c.picked, c.hunger, c.age, c.layTicks})
+	}
+	for _, egg := range g.eggs {
+		if egg.active {
+			save.Eggs = append(save.Eggs, SaveChicken{X: egg.pos.x, Y: egg.pos.y, Active: true, Pickable: egg.pickable, Age: egg.age})
+		}
+	}
+	for _, b := range g.buildingSites {
+		sb := SaveBuilding{b.blueprint.Name, b.pos.x, b.pos.y, b.delivered, b.work, nil}
+		if b.store != nil {
+			sb.Storage = b.store.inv.Stacks()
+		}
+		save.Buildings = append(save.Buildings, sb)
+	}
+	for _, in := range g.interiors {
+		if in.store != nil {
+			if save.Interiors == nil {
+				save.Interiors = make(map[string][]ItemStack)
+			}
+			save.Interiors[in.Name] = in.store.inv.Stacks()
+		}
+	}
+	content, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		log.Println("save:", err)
+		return
+	}
+	if err := os.MkdirAll(saveDir, 0755); err != nil {
+		log.Println("save:", err)
+		return
+	}
+	if err := os.WriteFile(saveFile, content, 0644); err != nil {
+		log.Println("save:", err)
+		return
+	}
+	if err := g.farmJSON.Save(farmFile); err != nil {
+		log.Println("save:", err)
+		return
+	}
+	saveProfile() // the lifetime stats are saved with the game
+	playSound(audioChest)
+}
+
+// F9 key load the saved game
+func (g *Game) loadGame() {
+	content, err := os.ReadFile(saveFile)
+	if err != nil {
+		log.Println("load:", err)
+		return
+	}
+	var save SaveGame
+	if err := json.Unmarshal(content, &save); err != nil {
+		log.Println("load:", err)
+		return
+	}
+	farm, err := tilemaps.NewTilemapJSON(farmFile)
+	if err != nil || farm.Layer(farmLayer) == nil {
+		log.Println("load: farmland missing", err)
+		return
+	}
+	// start from a new village, the saved tier unlock the houses and workers again
+	g.restart()
+	g.farmJSON = farm
+	g.clock.ticks = save.Ticks
+	g.workersHome = g.nightTime()
+	g.Player.inv.SetStacks(save.Inventory)
+	g.tilled = orEmpty(save.Tilled)
+	g.sales = save.Progress.Sales
+	g.buddaVisits = save.Progress.BuddaVisits
+	g.harvests = save.Progress.Harvests
+	g.chickensDelivered = save.Progress.ChickensDelivered
+	for g.tier < save.Progress.Tier && g.tier+1 < len(g.tiers.Tiers) {
+		g.tier++
+		g.unlockTier(g.tiers.Tiers[g.tier].Unlock)
+	}
+	if p := save.Player; p != nil {
+		g.setScene(p.Scene)
+		g.Player.pos = Point{p.X, p.Y}
+		g.Player.prePos = g.Player.pos
+		g.Player.wallet = p.Wallet
+		g.Player.basketSize = p.BasketSize
+	}
+	if m := save.Market; m != nil {
+		for _, it := range g.market.items {
+			if price, ok := m.Prices[it.item]; ok {
+				it.price = price
+			}
+		}
+		for _, t := range m.Log {
+			g.market.record(Transaction{t.Day, t.Item, t.Price, t.Buy})
+		}
+	}
+
+	// worker fields and contracts, planted plants come from the save
+	g.plants = g.plants[:len(g.workers)]
+	for i, sw := range save.Workers[:min(len(save.Workers), len(g.workers))] {
+		w, field := g.workers[i], g.plants[i]
+		w.morale = sw.Morale
+		if c := sw.Contract; c != nil {
+			w.contract = &Contract{c.Wage, c.Cycles, c.Done, c.Unpaid, c.Overdue}
+		}
+		if crop, ok := g.crops.Get(field.variety); ok {
+			field.growTicks = sw.Field.GrowTicks
+			field.frame = crop.Frame(field.growTicks)
+			field.pickable = crop.Ripe(field.growTicks)
+		}
+		field.active = sw.Field.Active
+		field.picked = sw.Field.Picked
+	}
+	for _, p := range save.Plants {
+		crop, ok := g.crops.Get(p.Variety)
+		if !ok {
+			continue
+		}
+		plant := g.newPlant(p.Variety, Point{p.X, p.Y})
+		plant.growTicks = p.GrowTicks
+		plant.frame = crop.Frame(plant.growTicks)
+		plant.pickable = crop.Ripe(plant.growTicks)
+		g.plants = append(g.plants, plant)
+	}
+	g.closeChest()
+	for _, in := range g.interiors {
+		if in.store != nil {
+			in.store.inv.SetStacks(save.Interiors[in.Name])
+		}
+	}
+	g.chests = nil
+	for _, c := range save.Chests {
+		chest := g.newChest(Point{c.X, c.Y})
+		chest.scene = c.Scene
+		chest.inv.SetStacks(c.Inventory)
+		g.chests = append(g.chests, chest)
+	}
+	if save.Chickens != nil {
+		g.chickens = g.chickens[:0]
+		for _, sc := range save.Chickens {
+			g.chickens = append(g.chickens, &Objects{
+				Sprite: &Sprite{
+					img:     g.chickenImg,
+					pos:     Point{sc.X, sc.Y},
+					rectPos: image.Rect(0, 0, imgSize/2, imgSize/2),
+					anim:    newRandomAnimator(chickenAnim, "walk"),
+					active:  sc.Active,
+				},
+				variety:  chicken,
+				dest:     Point{sc.X, sc.Y},
+				pickable: sc.Pickable,
+				picked:   sc.Picked,
+				hunger:   sc.Hunger,
+				age:      sc.Age,
+				layTicks: sc.LayTicks,
+			})
+		}
+	}
+	for _, egg := range g.eggs {
+		egg.active = false
+	}
+	for _, se := range save.Eggs {
+		g.layEgg(Point{se.X, se.Y}).age = se.Age
+	}
+	g.coopFeed = save.CoopFeed
+	g.flags = make(map[string]bool)
+	for _, flag := range save.Flags {
+		g.flags[flag] = true
+	}
+	g.quests = nil
+	for _, sq := range save.Quests {
+		data, ok := g.questsData.Get(sq.Name)
+		if !ok {
+			continue // quest removed from quests.json
+		}
+		q := &Quest{Quest: data, progress: make([]int, len(data.Objectives)), done: sq.Done}
+		copy(q.progress, sq.Progress)
+		g.quests = append(g.quests, q)
+	}
+	if save.Weather.State != "" {
+		g.weather = NewWeather(save.Weather.Seed, save.Weather.Draws)
+		g.weather.state = save.Weather.State
+		g.weather.wind = save.Weather.Wind
+	}
+	g.buildingSites = nil
+	for _, sb := range save.Buildings {
+		bp, ok := g.buildings.Get(sb.Blueprint)
+		if !ok {
+			continue
+		}
+		b := g.newBuilding(bp, Point{sb.X, sb.Y})
+		b.delivered = orEmpty(sb.Delivered)
+		b.work = sb.Work
+		if b.done() {
+			g.finishBuilding(b)
+		}
+		if b.store != nil {
+			b.store.inv.SetStacks(sb.Storage)
+		}
+		g.buildingSites = append(g.buildingSites, b)
+	}
+	playSound(audioSecret)
+}
+
+// empty map instead of nil map
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
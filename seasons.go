@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	calendarX    = 170
+	calendarY    = 60
+	calendarCell = 22
+)
+
+// Seasonal is an image with one palette for every season
+type Seasonal [len(seasonNames)]*ebiten.Image
+
+// new seasonal images. Grass is green in spring, warm in summer,
+// orange in autumn and white with snow in winter
+func newSeasonal(src image.Image) *Seasonal {
+	var s Seasonal
+	for season := range seasonNames {
+		s[season] = ebiten.NewImageFromImage(seasonPalette(src, season))
+	}
+	return &s
+}
+
+// swap the palette of green pixels for season
+func seasonPalette(src image.Image, season int) image.Image {
+	b := src.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			if c.G > c.R && c.G > c.B { // plants and grass
+				c = seasonColor(c, season)
+			}
+			dst.SetNRGBA(x, y, c)
+		}
+	}
+	return dst
+}
+
+// green color in season
+func seasonColor(c color.NRGBA, season int) color.NRGBA {
+	r, g, b := float64(c.R), float64(c.G), float64(c.B)
+	switch seasonNames[season] {
+	case "summer":
+		r, b = r*1.15, b*0.85
+	case "autumn":
+		r, g, b = g*1.1, g*0.75, b*0.5
+	case "winter":
+		lum := (r + g + b) / 3
+		r, g, b = lum*0.35+170, lum*0.35+178, lum*0.35+195
+	}
+	return color.NRGBA{clampByte(r), clampByte(g), clampByte(b), c.A}
+}
+
+func clampByte(v float64) uint8 {
+	return uint8(max(0, min(255, v)))
+}
+
+// C key show or hide the calendar
+func (g *Game) calendarKey() {
+	g.calendarOpen = !g.calendarOpen
+}
+
+// crops that can grow this season
+func (g *Game) seasonCrops() []string {
+	var names []string
+	for _, crop := range g.crops.Crops {
+		if crop.InSeason(g.clock.SeasonName()) {
+			names = append(names, crop.Name)
+		}
+	}
+	return names
+}
+
+// draw calendar with all days in the year and the crops in season
+func (g *Game) drawCalendar(screen *ebiten.Image) {
+	if !g.calendarOpen {
+		return
+	}
+	w := float32(80 + seasonDays*calendarCell + 16)
+	h := float32(30 + len(seasonNames)*calendarCell + 36)
+	vector.DrawFilledRect(screen, calendarX, calendarY, w, h, blue_transp, true)
+	vector.StrokeRect(screen, calendarX, calendarY, w, h, 1, yellow, true)
+	addTextAt(screen, 10, fmt.Sprintf("Calendar  year %d", g.clock.Year()), yellow, calendarX+8, calendarY+6)
+
+	for season, name := range seasonNames {
+		y := float32(calendarY + 26 + season*calendarCell)
+		c := color.Color(white)
+		if season == g.clock.Season() {
+			c = yellow
+		}
+		addTextAt(screen, 10, name, c, calendarX+8, float64(y)+5)
+		for day := range seasonDays {
+			x := float32(calendarX + 80 + day*calendarCell)
+			if season == g.clock.Season() && day+1 == g.clock.DayOfSeason() {
+				vector.DrawFilledRect(screen, x, y, calendarCell-2, calendarCell-2, orange, true)
+			} else if season < g.clock.Season() || season == g.clock.Season() && day+1 < g.clock.DayOfSeason() {
+				vector.DrawFilledRect(screen, x, y, calendarCell-2, calendarCell-2, blue_rect, true)
+			}
+			vector.StrokeRect(screen, x, y, calendarCell-2, calendarCell-2, 1, white, true)
+			addTextAt(screen, 8, fmt.Sprint(day+1), white, float64(x)+4, float64(y)+5)
+		}
+	}
+	crops := "nothing grow in " + g.clock.SeasonName()
+	if names := g.seasonCrops(); len(names) > 0 {
+		crops = "in season: " + strings.Join(names, ", ")
+	}
+	addTextAt(screen, 10, crops, white, calendarX+8, float64(calendarY)+float64(h)-28)
+}
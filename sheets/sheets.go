@@ -0,0 +1,203 @@
+package sheets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path"
+	"slices"
+)
+
+// Sprite sheets exported from Aseprite (File > Export Sprite Sheet, json
+// hash or array with tags and slices) or TexturePacker (JSON hash or array,
+// animations from the Phaser/Pixi exporters). Frames, tags and slices are
+// looked up by name, so the game never has Go coordinates for the art.
+
+// Frame is one named frame in the sheet. Duration is in milliseconds, 0 if
+// the sheet has no durations
+type Frame struct {
+	Name     string
+	Rect     image.Rectangle
+	Duration int
+}
+
+// Anim is frames in play order. Direction is forward, reverse, pingpong
+// or pingpong_reverse. Once when the animation play one time
+type Anim struct {
+	Frames    []Frame
+	Direction string
+	Once      bool
+}
+
+type SheetJSON struct {
+	Image  string // image file, relative to the working directory
+	Frames []Frame
+	byName map[string]int
+	anims  map[string]*Anim
+	slices map[string]image.Rectangle
+}
+
+type rect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+func (r rect) Rect() image.Rectangle {
+	return image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+}
+
+type frameJSON struct {
+	Filename string `json:"filename"` // array export, hash export has the name as key
+	Frame    rect   `json:"frame"`
+	Rotated  bool   `json:"rotated"`
+	Duration int    `json:"duration"`
+}
+
+type tagJSON struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+	Repeat    string `json:"repeat"` // Aseprite 1.3, "1" play once
+}
+
+type sliceJSON struct {
+	Name string `json:"name"`
+	Keys []struct {
+		Frame  int  `json:"frame"`
+		Bounds rect `json:"bounds"`
+	} `json:"keys"`
+}
+
+type sheetJSON struct {
+	Frames     json.RawMessage     `json:"frames"`
+	Animations map[string][]string `json:"animations"` // TexturePacker
+	Meta       struct {
+		App       string      `json:"app"`
+		Image     string      `json:"image"`
+		FrameTags []tagJSON   `json:"frameTags"` // Aseprite
+		Slices    []sliceJSON `json:"slices"`    // Aseprite
+	} `json:"meta"`
+}
+
+func NewSheetJSON(filepath string) (*SheetJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var raw sheetJSON
+	err = json.Unmarshal(content, &raw)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := decodeFrames(raw.Frames)
+	if err != nil {
+		return nil, fmt.Errorf("sheets: %s: %w", filepath, err)
+	}
+	s := &SheetJSON{
+		Image:  path.Join(path.Dir(filepath), raw.Meta.Image),
+		byName: make(map[string]int),
+		anims:  make(map[string]*Anim),
+		slices: make(map[string]image.Rectangle),
+	}
+	for i, f := range frames {
+		if f.Rotated {
+			return nil, fmt.Errorf("sheets: %s: frame %s is rotated, export without rotation", filepath, f.Filename)
+		}
+		s.Frames = append(s.Frames, Frame{Name: f.Filename, Rect: f.Frame.Rect(), Duration: f.Duration})
+		s.byName[f.Filename] = i
+	}
+	for _, t := range raw.Meta.FrameTags {
+		if t.From < 0 || t.To >= len(s.Frames) || t.From > t.To {
+			return nil, fmt.Errorf("sheets: %s: tag %s is out of the frames", filepath, t.Name)
+		}
+		a := &Anim{Direction: t.Direction, Once: t.Repeat == "1"}
+		a.Frames = append(a.Frames, s.Frames[t.From:t.To+1]...)
+		if a.Direction == "" {
+			a.Direction = "forward"
+		}
+		if a.Direction == "reverse" || a.Direction == "pingpong_reverse" {
+			slices.Reverse(a.Frames)
+		}
+		s.anims[t.Name] = a
+	}
+	for name, names := range raw.Animations {
+		a := &Anim{Direction: "forward"}
+		for _, n := range names {
+			i, ok := s.byName[n]
+			if !ok {
+				return nil, fmt.Errorf("sheets: %s: animation %s has no frame %s", filepath, name, n)
+			}
+			a.Frames = append(a.Frames, s.Frames[i])
+		}
+		s.anims[name] = a
+	}
+	for _, sl := range raw.Meta.Slices {
+		if len(sl.Keys) == 0 {
+			return nil, fmt.Errorf("sheets: %s: slice %s has no keys", filepath, sl.Name)
+		}
+		s.slices[sl.Name] = sl.Keys[0].Bounds.Rect() // the game use the first key
+	}
+	return s, nil
+}
+
+// frames from a json array, or a json hash in the order of the file
+func decodeFrames(data json.RawMessage) ([]frameJSON, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no frames")
+	}
+	var frames []frameJSON
+	if data[0] == '[' {
+		err := json.Unmarshal(data, &frames)
+		return frames, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil { // {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var f frameJSON
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+		f.Filename = key.(string)
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
+
+// Rect of a slice or a frame by name. Slices are checked first
+func (s *SheetJSON) Rect(name string) (image.Rectangle, bool) {
+	if r, ok := s.slices[name]; ok {
+		return r, true
+	}
+	if i, ok := s.byName[name]; ok {
+		return s.Frames[i].Rect, true
+	}
+	return image.Rectangle{}, false
+}
+
+// Anim by tag or animation name
+func (s *SheetJSON) Anim(name string) (*Anim, bool) {
+	a, ok := s.anims[name]
+	return a, ok
+}
+
+// AnimNames of all tags and animations
+func (s *SheetJSON) AnimNames() []string {
+	var names []string
+	for name := range s.anims {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,113 @@
+package tiers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Rule is one requirement, like {"stat": "sales", "op": ">=", "value": 4}
+type Rule struct {
+	Stat  string `json:"stat"`
+	Op    string `json:"op"`
+	Value int    `json:"value"`
+}
+
+// Buildings to show and hide, by house variety
+type Buildings struct {
+	Show []string `json:"show"`
+	Hide []string `json:"hide"`
+}
+
+// Unlock is what the village get when it reach a tier
+type Unlock struct {
+	Workers   []int     `json:"workers"`
+	Scene     *int      `json:"scene"`
+	Buildings Buildings `json:"buildings"`
+}
+
+// Tier is one step in the village progression
+type Tier struct {
+	Name     string `json:"name"`
+	Requires []Rule `json:"requires"`
+	Unlock   Unlock `json:"unlock"`
+}
+
+type TiersJSON struct {
+	Tiers []Tier `json:"tiers"`
+}
+
+func NewTiersJSON(filepath string) (*TiersJSON, error) {
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	var tiersJSON TiersJSON
+	err = json.Unmarshal(content, &tiersJSON)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tiersJSON.Tiers {
+		for _, r := range t.Requires {
+			if _, err := r.check(0); err != nil {
+				return nil, fmt.Errorf("tiers: %s: %w", t.Name, err)
+			}
+		}
+	}
+	return &tiersJSON, nil
+}
+
+// Check rule against the game stats
+func (r Rule) Check(stats map[string]int) bool {
+	ok, _ := r.check(stats[r.Stat])
+	return ok
+}
+
+// Validate the op of the rule
+func (r Rule) Validate() error {
+	_, err := r.check(0)
+	return err
+}
+
+func (r Rule) check(v int) (bool, error) {
+	switch r.Op {
+	case ">=", "":
+		return v >= r.Value, nil
+	case ">":
+		return v > r.Value, nil
+	case "<=":
+		return v <= r.Value, nil
+	case "<":
+		return v < r.Value, nil
+	case "==":
+		return v == r.Value, nil
+	}
+	return false, fmt.Errorf("unknown op %q", r.Op)
+}
+
+// Reached when all requirements pass
+func (t Tier) Reached(stats map[string]int) bool {
+	for _, r := range t.Requires {
+		if !r.Check(stats) {
+			return false
+		}
+	}
+	return true
+}
+
+// Progress 0-1 toward the tier, the mean of all requirements
+func (t Tier) Progress(stats map[string]int) float64 {
+	if len(t.Requires) == 0 {
+		return 1
+	}
+	sum := 0.0
+	for _, r := range t.Requires {
+		switch {
+		case r.Check(stats):
+			sum++
+		case r.Value > 0 && (r.Op == ">=" || r.Op == ">" || r.Op == ""):
+			sum += min(1, float64(stats[r.Stat])/float64(r.Value))
+		}
+	}
+	return sum / float64(len(t.Requires))
+}
@@ -6,9 +6,11 @@ import (
 )
 
 type TilemapLayers struct {
-	Data   []int `json:"data"`
-	Width  int   `json:"width"`
-	Height int   `json:"height"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Data   []int  `json:"data"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 type TilemapJSON struct {
 	Layers []TilemapLayers `json:"layers"`
@@ -26,3 +28,57 @@ func NewTilemapJSON(filepath string) (*TilemapJSON, error) {
 	}
 	return &tilemapJSON, nil
 }
+
+// Save tilemap with all layers to filepath
+func (t *TilemapJSON) Save(filepath string) error {
+	content, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath, content, 0644)
+}
+
+// Layer by name, nil if the tilemap has no layer with that name
+func (t *TilemapJSON) Layer(name string) *TilemapLayers {
+	for i := range t.Layers {
+		if t.Layers[i].Name == name {
+			return &t.Layers[i]
+		}
+	}
+	return nil
+}
+
+// AddLayer add an empty tile layer on top, same size as the first layer
+func (t *TilemapJSON) AddLayer(name string, width, height int) *TilemapLayers {
+	if len(t.Layers) > 0 {
+		width, height = t.Layers[0].Width, t.Layers[0].Height
+	}
+	t.Layers = append(t.Layers, TilemapLayers{
+		Name:   name,
+		Type:   "tilelayer",
+		Data:   make([]int, width*height),
+		Width:  width,
+		Height: height,
+	})
+	return &t.Layers[len(t.Layers)-1]
+}
+
+// Tile id at tile x,y. 0 is an empty tile
+func (l *TilemapLayers) Tile(x, y int) int {
+	if !l.Inside(x, y) {
+		return 0
+	}
+	return l.Data[y*l.Width+x]
+}
+
+// SetTile set tile id at tile x,y
+func (l *TilemapLayers) SetTile(x, y, id int) {
+	if l.Inside(x, y) {
+		l.Data[y*l.Width+x] = id
+	}
+}
+
+// Inside check if tile x,y is inside the layer
+func (l *TilemapLayers) Inside(x, y int) bool {
+	return x >= 0 && y >= 0 && x < l.Width && y < l.Height
+}
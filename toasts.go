@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	maxToasts   = 3 // toasts on screen, the others wait in the queue
+	toastW      = 200
+	toastH      = 20
+	toastX      = screenWidth - toastW - 4
+	toastY      = 64 // below the HUD panel in the top right corner
+	toastFade   = 20 // ticks to fade out
+	floatTicks  = 50
+	floatSpeed  = 0.4
+	toastIconSz = 16
+)
+
+// toast priorities, higher priorities are shown first
+const (
+	toastInfo = iota
+	toastWarn
+	toastImportant
+)
+
+// Toast is a short notice in the HUD corner. Toasts with the same text are
+// one toast, a repeated notice start its time over
+type Toast struct {
+	text     string
+	icon     string // item icon, "" for none
+	c        color.RGBA
+	priority int
+	ticks    int // ticks on screen
+	age      int
+}
+
+// FloatText is text that rise and fade over the world, like "+2" over the budda
+type FloatText struct {
+	text string
+	pos  Point
+	c    color.RGBA
+	age  int
+}
+
+// ActionFailed is something the Player tried that didn't work, like a full wallet
+type ActionFailed struct {
+	Reason string
+	Item   string
+}
+
+// queue a toast, or start a toast with the same text over
+func (g *Game) notify(t Toast) {
+	for _, old := range g.toasts {
+		if old.text == t.text {
+			old.age = 0
+			return
+		}
+	}
+	g.toasts = append(g.toasts, &t)
+	slices.SortStableFunc(g.toasts, func(a, b *Toast) int { return b.priority - a.priority })
+}
+
+// text rising from pos
+func (g *Game) floatText(pos Point, text string, c color.RGBA) {
+	g.floats = append(g.floats, &FloatText{text: text, pos: pos, c: c})
+}
+
+// toasts and floating texts for the game events
+func subscribeToasts(b *EventBus) {
+	Subscribe(b, func(g *Game, e ActionFailed) {
+		g.notify(Toast{text: tr(e.Reason), icon: e.Item, c: orange, priority: toastWarn, ticks: 90})
+	})
+	Subscribe(b, func(g *Game, e AchievementUnlocked) {
+		g.notify(Toast{text: tr("Achievement unlocked") + ": " + e.Achievement.Name, icon: e.Achievement.Icon, c: yellow, priority: toastImportant, ticks: 240})
+	})
+	Subscribe(b, func(g *Game, e QuestStarted) {
+		g.notify(Toast{text: tr("New quest") + ": " + e.Quest.Name, c: white, priority: toastInfo, ticks: 150})
+	})
+	Subscribe(b, func(g *Game, e QuestCompleted) {
+		g.notify(Toast{text: tr("Quest done") + ": " + e.Quest.Name, c: yellow, priority: toastImportant, ticks: 180})
+	})
+	Subscribe(b, func(g *Game, e TierReached) {
+		g.notify(Toast{text: tr("Village") + ": " + g.tiers.Tiers[e.Tier].Name, c: yellow, priority: toastImportant, ticks: 180})
+	})
+	Subscribe(b, func(g *Game, e ItemSold) {
+		if pos, ok := g.buddaPos(); ok {
+			g.floatText(pos, fmt.Sprintf("+%d", e.Price), yellow)
+		}
+	})
+	Subscribe(b, func(g *Game, e ItemPicked) {
+		g.floatText(e.Pos, fmt.Sprintf("+%d", e.Count), white)
+	})
+	Subscribe(b, func(g *Game, e SceneChanged) { g.floats = nil }) // floating texts stay in their scene
+}
+
+// above the head of the budda in this scene
+func (g *Game) buddaPos() (Point, bool) {
+	for _, house := range g.house {
+		if house.variety == "budda" && house.active {
+			return Point{house.pos.x + 16, house.pos.y}, true
+		}
+	}
+	return Point{}, false
+}
+
+// age the toasts on screen and the floating texts
+func (g *Game) updateToasts() {
+	for i, t := range g.toasts {
+		if i < maxToasts {
+			t.age++
+		}
+	}
+	g.toasts = slices.DeleteFunc(g.toasts, func(t *Toast) bool { return t.age >= t.ticks })
+	for _, f := range g.floats {
+		f.age++
+		f.pos.y -= floatSpeed
+	}
+	g.floats = slices.DeleteFunc(g.floats, func(f *FloatText) bool { return f.age >= floatTicks })
+}
+
+// color c with alpha a, the colors are premultiplied
+func fadeColor(c color.RGBA, a float64) color.RGBA {
+	return color.RGBA{uint8(float64(c.R) * a), uint8(float64(c.G) * a), uint8(float64(c.B) * a), uint8(float64(c.A) * a)}
+}
+
+// draw the first toasts top down, they fade out at the end
+func (g *Game) drawToasts(screen *ebiten.Image) {
+	for i, t := range g.toasts[:min(len(g.toasts), maxToasts)] {
+		a := min(1, float64(t.ticks-t.age)/toastFade)
+		x, y := float32(toastX), float32(toastY+i*(toastH+4))
+		vector.DrawFilledRect(screen, x, y, toastW, toastH, fadeColor(blue_transp, a), true)
+		vector.StrokeRect(screen, x, y, toastW, toastH, 1, fadeColor(t.c, a), true)
+		textX := float64(x) + 6
+		if img, r := g.itemIcon(t.icon); img != nil {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(toastIconSz/float64(r.Dx()), toastIconSz/float64(r.Dy()))
+			op.GeoM.Translate(float64(x)+2, float64(y)+2)
+			op.ColorScale.ScaleAlpha(float32(a))
+			screen.DrawImage(subImage(img, r), op)
+			textX += toastIconSz
+		}
+		addTextAt(screen, 8, t.text, fadeColor(t.c, a), textX, float64(y)+5)
+	}
+}
+
+// draw the floating texts over the world
+func (g *Game) drawFloats(screen *ebiten.Image) {
+	for _, f := range g.floats {
+		a := 1 - float64(f.age)/floatTicks
+		addText(screen, 10, f.text, fadeColor(f.c, a), 2*f.pos.x, 2*f.pos.y)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	weatherClear  = "clear"
+	weatherCloudy = "cloudy"
+	weatherRain   = "rain"
+	weatherStorm  = "storm"
+	weatherSnow   = "snow"
+	weatherHours  = 3   // weather can change every 3 hours
+	lightningRate = 400 // one lightning in 400 ticks in a storm
+	flashTicks    = 6
+)
+
+// weather probabilities in percent for every season
+var weatherChance = map[string]map[string]int{
+	"spring": {weatherClear: 45, weatherCloudy: 30, weatherRain: 20, weatherStorm: 5},
+	"summer": {weatherClear: 60, weatherCloudy: 20, weatherRain: 10, weatherStorm: 10},
+	"autumn": {weatherClear: 30, weatherCloudy: 35, weatherRain: 25, weatherStorm: 10},
+	"winter": {weatherClear: 35, weatherCloudy: 30, weatherSnow: 35},
+}
+
+// next weather the state machine can go to from the current weather
+var weatherNext = map[string][]string{
+	weatherClear:  {weatherClear, weatherCloudy},
+	weatherCloudy: {weatherClear, weatherCloudy, weatherRain, weatherStorm, weatherSnow},
+	weatherRain:   {weatherCloudy, weatherRain, weatherStorm},
+	weatherStorm:  {weatherCloudy, weatherRain, weatherStorm},
+	weatherSnow:   {weatherCloudy, weatherSnow},
+}
+
+// particles, light and wind for every weather
+var weatherLook = map[string]struct {
+	drops int     // rain drops or snow flakes
+	dim   float32 // the world is darker
+	wind  float64 // max wind
+}{
+	weatherClear:  {0, 1, 0.3},
+	weatherCloudy: {0, 0.85, 0.8},
+	weatherRain:   {150, 0.7, 1},
+	weatherStorm:  {300, 0.55, 3},
+	weatherSnow:   {120, 0.9, 0.6},
+}
+
+// drop is one rain drop or snow flake
+type drop struct {
+	x, y, vx, vy float64
+}
+
+// countSource count the numbers drawn from the source
+type countSource struct {
+	rand.Source
+	draws int64
+}
+
+func (s *countSource) Int63() int64 {
+	s.draws++
+	return s.Source.Int63()
+}
+
+// Weather state machine. The random source is seeded so the same seed give the
+// same weather, the drops and the lightning are not part of it
+type Weather struct {
+	state string
+	wind  float64
+	seed  int64
+	src   *countSource
+	rng   *rand.Rand
+	drops []drop
+	flash int // ticks left of the lightning flash
+}
+
+// weather from seed, with draws numbers already drawn. A loaded game draw them
+// again and get the weather the saved game would get
+func NewWeather(seed, draws int64) *Weather {
+	src := &countSource{Source: rand.NewSource(seed)}
+	for range draws {
+		src.Int63()
+	}
+	return &Weather{
+		state: weatherClear,
+		seed:  seed,
+		src:   src,
+		rng:   rand.New(src),
+	}
+}
+
+// precipitation wet the farmland
+func (w *Weather) raining() bool {
+	return w.state == weatherRain || w.state == weatherStorm
+}
+
+// chickens run to the coop in bad weather
+func (w *Weather) bad() bool {
+	return w.raining() || w.state == weatherSnow
+}
+
+// next weather from the season probabilities
+func (w *Weather) roll(season string) {
+	total := 0
+	for _, next := range weatherNext[w.state] {
+		total += weatherChance[season][next]
+	}
+	if total == 0 {
+		w.set(weatherCloudy)
+		return
+	}
+	n := w.rng.Intn(total)
+	for _, next := range weatherNext[w.state] {
+		n -= weatherChance[season][next]
+		if n < 0 {
+			w.set(next)
+			return
+		}
+	}
+}
+
+// set weather and a new wind
+func (w *Weather) set(state string) {
+	w.state = state
+	w.wind = (w.rng.Float64()*2 - 1) * weatherLook[state].wind
+}
+
+// new drop at a random place at the top of the screen
+func (w *Weather) newDrop(anywhere bool) drop {
+	d := drop{x: rand.Float64() * screenWidth, y: -4}
+	if anywhere {
+		d.y = rand.Float64() * screenHeight
+	}
+	d.vx = w.wind
+	d.vy = 4 + rand.Float64()*2
+	if w.state == weatherSnow {
+		d.vx = w.wind + rand.Float64() - 0.5
+		d.vy = 0.5 + rand.Float64()*0.5
+	}
+	return d
+}
+
+// move drops, add or remove drops for the weather and start lightning in storms
+func (w *Weather) update() {
+	want := weatherLook[w.state].drops
+	for len(w.drops) < want {
+		w.drops = append(w.drops, w.newDrop(true))
+	}
+	w.drops = w.drops[:want]
+	for i := range w.drops {
+		d := &w.drops[i]
+		d.x += d.vx
+		d.y += d.vy
+		if d.y > screenHeight || d.x < -8 || d.x > screenWidth+8 {
+			*d = w.newDrop(false)
+		}
+	}
+	if w.flash > 0 {
+		w.flash--
+	}
+	if w.state == weatherStorm && rand.Intn(lightningRate) == 0 {
+		w.flash = flashTicks
+	}
+}
+
+// roll new weather every few hours
+func (g *Game) weatherHour() {
+	if g.clock.Hour()%weatherHours != 0 {
+		return
+	}
+	was := g.weather.state
+	g.weather.roll(g.clock.SeasonName())
+	if g.weather.state != was && g.weather.bad() {
+		for _, c := range g.chickens {
+			if c.roaming() {
+				c.dest = coopPoint() // run for cover
+			}
+		}
+	}
+	g.rainWater()
+}
+
+// rain water the farmland and the worker fields
+func (g *Game) rainWater() {
+	if !g.weather.raining() {
+		return
+	}
+	layer := g.farmJSON.Layer(farmLayer)
+	for i, id := range layer.Data {
+		if id == tileDry {
+			layer.Data[i] = tileWet
+		}
+	}
+	for _, plant := range g.plants {
+		if plant.worker != nil {
+			plant.water = waterTicks
+		}
+	}
+}
+
+// new destination for a roaming chicken, close to the coop in bad weather
+func (g *Game) chickenDest() Point {
+	if g.weather.bad() {
+		return coopPoint()
+	}
+	return randomPoint()
+}
+
+// weather dim the ambient light
+func (g *Game) weatherDim() float32 {
+	return weatherLook[g.weather.state].dim
+}
+
+// draw rain and snow
+func (g *Game) drawWeather(screen *ebiten.Image) {
+	w := g.weather
+	for _, d := range w.drops {
+		if w.state == weatherSnow {
+			vector.DrawFilledCircle(screen, float32(d.x), float32(d.y), 1, white, false)
+		} else {
+			vector.StrokeLine(screen, float32(d.x), float32(d.y), float32(d.x-d.vx*2), float32(d.y-d.vy*2), 1, color.RGBA{160, 180, 255, 160}, false)
+		}
+	}
+}
+
+// draw lightning flash on top of the night
+func (g *Game) drawLightning(screen *ebiten.Image) {
+	if g.weather.flash > 0 {
+		vector.DrawFilledRect(screen, 0, 0, screenWidth, screenHeight, color.RGBA{255, 255, 255, 120}, false)
+	}
+}
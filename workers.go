@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	workerWage     = 1       // coins per harvest cycle
+	contractCycles = 3       // harvest cycles in one contract
+	moraleMax      = 100     // happy worker
+	moraleStart    = 70      // morale of a new worker
+	moraleDropTime = 60 * 2  // ticks between morale drops while wages are unpaid
+	wageGraceTime  = 60 * 10 // ticks before unpaid wages start to hurt morale
+)
+
+// Contract between Player and worker. Wages are paid for every harvest cycle
+type Contract struct {
+	wage    int // coins per harvest cycle
+	cycles  int // harvest cycles left to work
+	done    int // harvest cycles done
+	unpaid  int // coins the worker is waiting for
+	overdue int // ticks since the worker was left unpaid
+}
+
+// hire worker if the Player can pay the first wage and the village has housing.
+// The first wage is paid up front, it's the wage for the first harvest cycle
+func (g *Game) hireWorker(w *Characters) {
+	if w.contract != nil || !w.active || g.Player.inv.Count(coinItem) < workerWage {
+		return
+	}
+	if g.hiredWorkers() >= g.housing() {
+		Publish(g, ActionFailed{"No house for a worker", ""})
+		return
+	}
+	g.Player.inv.Remove(coinItem, workerWage)
+	w.coin += workerWage // show the coin on the workers head
+	w.contract = &Contract{
+		wage:   workerWage,
+		cycles: contractCycles,
+	}
+	if w.morale == 0 {
+		w.morale = moraleStart
+	}
+	Publish(g, WorkerHired{w})
+}
+
+// pay unpaid wages with coins from the Player
+func (g *Game) payWorker(w *Characters) {
+	if w.contract == nil || w.contract.unpaid == 0 || g.Player.inv.Count(coinItem) == 0 {
+		return
+	}
+	pay := g.Player.inv.Remove(coinItem, w.contract.unpaid)
+	w.coin += pay
+	w.contract.unpaid -= pay
+	w.contract.overdue = 0
+	w.morale = min(moraleMax, w.morale+10*pay)
+	Publish(g, WorkerPaid{w, pay})
+	// contract is over when the last wage is paid
+	if w.contract.cycles == 0 && w.contract.unpaid == 0 {
+		w.contract = nil
+	}
+}
+
+// worker harvest cycle is done when the Player pick the workers plant
+func (g *Game) completeCycle(w *Characters) {
+	w.coin = 0 // drop coin when plant are picked
+	if w.contract == nil || w.contract.cycles == 0 {
+		return
+	}
+	paid := w.contract.unpaid == 0
+	w.contract.cycles--
+	w.contract.done++
+	if w.contract.done > 1 { // the first cycle was paid when the worker was hired
+		w.contract.unpaid += w.contract.wage
+	}
+	if paid {
+		w.morale = min(moraleMax, w.morale+5) // happy, all earlier wages are paid
+	}
+	if w.contract.cycles == 0 && w.contract.unpaid == 0 {
+		w.contract = nil // a one cycle contract is over without a pay
+	}
+}
+
+// worker is working the field while the contract has cycles left
+func (w *Characters) working() bool {
+	return w.active && w.contract != nil && w.contract.cycles > 0
+}
+
+// unpaid wages lower morale. Worker leave the village when morale is gone
+func (g *Game) updateContract(w *Characters) {
+	if w.contract == nil || w.contract.unpaid == 0 {
+		return
+	}
+	w.contract.overdue++
+	if w.contract.overdue > wageGraceTime && w.contract.overdue%moraleDropTime == 0 {
+		w.morale--
+	}
+	if w.morale <= 0 {
+		g.workerLeave(w)
+	}
+}
+
+// worker walk out of the village without a contract
+func (g *Game) workerLeave(w *Characters) {
+	w.contract = nil
+	w.coin = 0
+	w.morale = 0
+	w.active = false
+	w.dest = Point{-imgSize, w.pos.y}
+	playSound(audioFx)
+}
+
+// productivity in percent. A sad worker grow plants at half speed
+func (w *Characters) productivity() int {
+	return 50 + w.morale/2
+}
+
+// plant grow this tick if the worker is productive
+func (w *Characters) productive() bool {
+	return rand.Intn(100) < w.productivity()
+}
+
+// walking speed for workers, slower when morale is low
+func (w *Characters) walkSpeed() float64 {
+	return w.speed * (0.5 + float64(w.morale)/(2*moraleMax))
+}
+
+// Tab key show or hide the worker panel
+func (g *Game) workerPanelKey() {
+	g.workerPanel = !g.workerPanel
+}
+
+// draw worker panel with contract, wages and morale for all active workers
+func (g *Game) drawWorkerPanel(screen *ebiten.Image) {
+	if !g.workerPanel {
+		return
+	}
+	x, y := float32(20), float32(20)
+	rows := 0
+	for _, w := range g.workers {
+		if w.active || w.contract != nil {
+			rows++
+		}
+	}
+	vector.DrawFilledRect(screen, x, y, 280, float32(30+rows*14), blue_transp, true)
+	addTextAt(screen, 10, "Workers   contract   owed   morale", yellow, float64(x)+8, float64(y)+6)
+
+	row := 0
+	for i, w := range g.workers {
+		if !w.active && w.contract == nil {
+			continue
+		}
+		line := fmt.Sprintf("#%d  idle", i+1)
+		c := color.Color(white)
+		if w.contract != nil {
+			line = fmt.Sprintf("#%d  %d/%d cycles   %d coin   %d%%",
+				i+1, w.contract.done, w.contract.done+w.contract.cycles, w.contract.unpaid, w.morale)
+			if w.contract.unpaid > 0 {
+				c = orange
+			}
+			if w.morale < moraleStart/2 {
+				c = red
+			}
+		}
+		addTextAt(screen, 10, line, c, float64(x)+8, float64(y)+22+float64(row*14))
+		row++
+	}
+}